@@ -21,8 +21,5 @@ func PlantSeed(planter, seed *quantum.QuantumObject) {
 
 // Remember моделирует "вспоминание": observer копирует распределение target.
 func Remember(observer, target *quantum.QuantumObject) {
-	observer.CoordDist = make(map[[2]int]float64)
-	for k, v := range target.CoordDist {
-		observer.CoordDist[k] = v
-	}
+	observer.SetDistribution(target.Distribution())
 }
@@ -0,0 +1,151 @@
+package quantum
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+)
+
+// cancelledAmplitude — амплитуды меньшего модуля после сложения считаются
+// погашенными: без порога ошибки округления фазы оставляют ненулевой остаток.
+const cancelledAmplitude = 1e-12
+
+// QuantumAmplitudeObject — объект с комплексными амплитудами вместо
+// вероятностей: вероятность клетки равна |a|² (правило Борна).
+// Амплитуды складываются с учётом фазы, что позволяет описывать интерференцию.
+type QuantumAmplitudeObject struct {
+	Name          string
+	AmplitudeDist map[[2]int]complex128 // (x,y) -> амплитуда (до нормировки)
+	IsCollapsed   bool
+	FinalCoord    [2]int
+
+	rng *lockedRand // собственный источник случайности; nil — глобальный
+}
+
+// AmplitudeObject — краткое имя QuantumAmplitudeObject.
+type AmplitudeObject = QuantumAmplitudeObject
+
+// NewQuantumAmplitudeObject создаёт объект с заданными амплитудами.
+func NewQuantumAmplitudeObject(name string, amps map[[2]int]complex128) *QuantumAmplitudeObject {
+	return &QuantumAmplitudeObject{
+		Name:          name,
+		AmplitudeDist: amps,
+	}
+}
+
+// SetRand задаёт собственный генератор объекта. nil возвращает глобальный генератор.
+func (q *QuantumAmplitudeObject) SetRand(rng *rand.Rand) {
+	q.rng = newLockedRand(rng)
+}
+
+// NormalizeDistribution нормирует амплитуды так, чтобы сумма |a|² стала 1.
+// Относительные фазы сохраняются.
+func (q *QuantumAmplitudeObject) NormalizeDistribution() {
+	total := 0.0
+	for _, a := range q.AmplitudeDist {
+		total += born(a)
+	}
+	if total > 0 {
+		scale := complex(1/math.Sqrt(total), 0)
+		for c, a := range q.AmplitudeDist {
+			q.AmplitudeDist[c] = a * scale
+		}
+	}
+}
+
+// ProbabilityDist возвращает нормированные вероятности |a|² по клеткам.
+// Клетки с нулевой амплитудой опускаются.
+func (q *QuantumAmplitudeObject) ProbabilityDist() map[[2]int]float64 {
+	out := make(map[[2]int]float64, len(q.AmplitudeDist))
+	total := 0.0
+	for c, a := range q.AmplitudeDist {
+		if p := born(a); p > 0 {
+			out[c] = p
+			total += p
+		}
+	}
+	for c, p := range out {
+		out[c] = p / total
+	}
+	return out
+}
+
+// Collapse выбирает координату с вероятностью |a|² и оставляет в ней
+// единичную амплитуду. Если объект уже коллапсирован, ничего не делает.
+func (q *QuantumAmplitudeObject) Collapse() {
+	if q.IsCollapsed {
+		return
+	}
+	probs := q.ProbabilityDist()
+	r := drawFloat(q.rng, nil)
+	coords := sortedCoords(probs)
+	if len(coords) == 0 {
+		return
+	}
+	// последняя клетка — запасной выбор на случай ошибки округления
+	chosen := coords[len(coords)-1]
+	cumulative := 0.0
+	for _, coord := range coords {
+		cumulative += probs[coord]
+		if r <= cumulative {
+			chosen = coord
+			break
+		}
+	}
+	q.FinalCoord = chosen
+	q.IsCollapsed = true
+	q.AmplitudeDist = map[[2]int]complex128{chosen: 1}
+}
+
+// Interfere возвращает новый объект с амплитудами a₁ + e^{iφ}·a₂, где a₂ —
+// амплитуды other. При phase = π совпадающие амплитуды гасят друг друга.
+// Исходные объекты не меняются; имя результата — "q+other".
+func (q *QuantumAmplitudeObject) Interfere(other *QuantumAmplitudeObject, phase float64) *QuantumAmplitudeObject {
+	rot := cmplx.Exp(complex(0, phase))
+	amps := make(map[[2]int]complex128, len(q.AmplitudeDist)+len(other.AmplitudeDist))
+	for c, a := range q.AmplitudeDist {
+		amps[c] += a
+	}
+	for c, a := range other.AmplitudeDist {
+		amps[c] += rot * a
+	}
+	for c, a := range amps {
+		if cmplx.Abs(a) < cancelledAmplitude {
+			delete(amps, c)
+		}
+	}
+	return NewQuantumAmplitudeObject(q.Name+"+"+other.Name, amps)
+}
+
+// Superpose возвращает нормированную суперпозицию a и b: амплитуды
+// складываются поклеточно, так что противоположные по знаку гасят друг друга.
+func Superpose(a, b *AmplitudeObject) *AmplitudeObject {
+	obj := a.Interfere(b, 0)
+	obj.NormalizeDistribution()
+	return obj
+}
+
+// ToQuantumObject возвращает обычный объект с вероятностями |a|².
+// Фазы при этом теряются.
+func (q *QuantumAmplitudeObject) ToQuantumObject() *QuantumObject {
+	obj := NewQuantumObject(q.Name, q.ProbabilityDist())
+	obj.IsCollapsed, obj.FinalCoord = q.IsCollapsed, q.FinalCoord
+	if q.IsCollapsed {
+		obj.CoordDist = map[[2]int]float64{q.FinalCoord: 1}
+	}
+	obj.rng = q.rng
+	return obj
+}
+
+func (q *QuantumAmplitudeObject) String() string {
+	if q.IsCollapsed {
+		return fmt.Sprintf("<%s collapsed at (%d, %d)>", q.Name, q.FinalCoord[0], q.FinalCoord[1])
+	}
+	return fmt.Sprintf("<%s in superposition (uncollapsed)>", q.Name)
+}
+
+// born возвращает |a|².
+func born(a complex128) float64 {
+	return real(a)*real(a) + imag(a)*imag(a)
+}
@@ -0,0 +1,60 @@
+package quantum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAmplitudeProbabilities(t *testing.T) {
+	obj := NewQuantumAmplitudeObject("A", map[[2]int]complex128{{0, 0}: 1, {1, 0}: 1i, {2, 0}: complex(1, 1)})
+	probs := obj.ProbabilityDist()
+	if math.Abs(probs[[2]int{0, 0}]-0.25) > 1e-12 || math.Abs(probs[[2]int{2, 0}]-0.5) > 1e-12 {
+		t.Errorf("probabilities should follow |a|², got %v", probs)
+	}
+}
+
+func TestAmplitudeInterference(t *testing.T) {
+	slit1 := NewQuantumAmplitudeObject("S1", map[[2]int]complex128{{0, 0}: 1, {1, 0}: 1})
+	slit2 := NewQuantumAmplitudeObject("S2", map[[2]int]complex128{{1, 0}: 1, {2, 0}: 1})
+
+	dark := slit1.Interfere(slit2, math.Pi).ProbabilityDist()
+	if _, ok := dark[[2]int{1, 0}]; ok {
+		t.Errorf("opposite phases should cancel the shared cell, got %v", dark)
+	}
+	bright := slit1.Interfere(slit2, 0).ProbabilityDist()
+	if math.Abs(bright[[2]int{1, 0}]-2.0/3) > 1e-12 {
+		t.Errorf("equal phases should reinforce the shared cell, got %v", bright)
+	}
+}
+
+func TestAmplitudeCollapse(t *testing.T) {
+	obj := NewQuantumAmplitudeObject("A", map[[2]int]complex128{{0, 0}: 0, {3, 3}: -1i})
+	obj.SetRand(rand.New(rand.NewSource(1)))
+	obj.Collapse()
+	if !obj.IsCollapsed || obj.FinalCoord != [2]int{3, 3} {
+		t.Errorf("only the non-zero amplitude can be observed, got %v", obj)
+	}
+	if q := obj.ToQuantumObject(); !q.IsCollapsed || q.FinalCoord != obj.FinalCoord {
+		t.Errorf("conversion should keep the collapse, got %v", q)
+	}
+}
+
+func TestSuperposeCancels(t *testing.T) {
+	a := NewQuantumAmplitudeObject("A", map[[2]int]complex128{{0, 0}: 1, {1, 0}: 1})
+	b := NewQuantumAmplitudeObject("B", map[[2]int]complex128{{0, 0}: -1, {2, 0}: 1i})
+	s := Superpose(a, b)
+	if _, ok := s.AmplitudeDist[[2]int{0, 0}]; ok {
+		t.Errorf("opposite amplitudes should cancel, got %v", s.AmplitudeDist)
+	}
+	norm := 0.0
+	for _, amp := range s.AmplitudeDist {
+		norm += born(amp)
+	}
+	if math.Abs(norm-1) > 1e-12 {
+		t.Errorf("superposition should be normalized, sum |a|² = %f", norm)
+	}
+	if phase := s.AmplitudeDist[[2]int{2, 0}]; math.Abs(real(phase)) > 1e-12 || imag(phase) <= 0 {
+		t.Errorf("normalization must keep phases, got %v", phase)
+	}
+}
@@ -0,0 +1,99 @@
+package quantum
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// AuditEvent — запись журнала измерений: операция, затронутые объекты
+// (в порядке аргументов или Objects), их координаты после операции
+// и энтропия до и после.
+type AuditEvent struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Operation     string    `json:"operation"`
+	ObjectNames   []string  `json:"objectNames"`
+	ResultCoords  [][2]int  `json:"resultCoords"` // FinalCoord; для несколлапсированного объекта — нулевая
+	Collapsed     []bool    `json:"collapsed"`
+	EntropyBefore []float64 `json:"entropyBefore"`
+	EntropyAfter  []float64 `json:"entropyAfter"`
+}
+
+// EnableAuditLog включает журнал: каждый вызов MeasureInteraction (и его
+// вариантов), CollapseAll, CollapseAllParallel и Collapse/CollapseWith
+// объектов мира добавляет AuditEvent. Повторный вызов журнал не очищает.
+func (w *World) EnableAuditLog() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.auditing = true
+}
+
+// AuditLog возвращает копию журнала в порядке записи.
+func (w *World) AuditLog() []AuditEvent {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.audit)
+}
+
+// ExportAuditJSON записывает весь журнал JSON-массивом.
+func (w *World) ExportAuditJSON(out io.Writer) error {
+	return json.NewEncoder(out).Encode(w.AuditLog())
+}
+
+// track вызывается перед изменяющей операцией op над objs: сохраняет снимок
+// для Undo и начинает запись журнала. Возвращённую функцию нужно вызвать
+// после операции; в строгом режиме она проверяет мир. Для nil-мира ничего не делает.
+func (w *World) track(op string, objs ...*QuantumObject) func() {
+	if w == nil {
+		return func() {}
+	}
+	w.record()
+	w.mu.RLock()
+	auditing, strict := w.auditing, w.strict
+	w.mu.RUnlock()
+	check := func() {
+		if strict {
+			w.mustValidate(op)
+		}
+	}
+	if !auditing {
+		return check
+	}
+
+	ev := AuditEvent{
+		Timestamp:     time.Now(),
+		Operation:     op,
+		ObjectNames:   make([]string, len(objs)),
+		EntropyBefore: make([]float64, len(objs)),
+	}
+	for i, obj := range objs {
+		ev.ObjectNames[i] = obj.Name
+		ev.EntropyBefore[i] = obj.Entropy()
+	}
+	return func() {
+		ev.ResultCoords = make([][2]int, len(objs))
+		ev.Collapsed = make([]bool, len(objs))
+		ev.EntropyAfter = make([]float64, len(objs))
+		for i, obj := range objs {
+			obj.mu.RLock()
+			if obj.IsCollapsed {
+				ev.ResultCoords[i], ev.Collapsed[i] = obj.FinalCoord, true
+			}
+			obj.mu.RUnlock()
+			ev.EntropyAfter[i] = obj.Entropy()
+		}
+		w.mu.Lock()
+		w.audit = append(w.audit, ev)
+		w.mu.Unlock()
+		check()
+	}
+}
+
+// mustValidate паникует, если после операции op мир не проходит Validate.
+func (w *World) mustValidate(op string) {
+	if err := w.Validate(); err != nil {
+		panic(fmt.Errorf("%s left the world invalid: %w", op, err))
+	}
+}
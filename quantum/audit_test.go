@@ -0,0 +1,60 @@
+package quantum
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	world := NewWorld(WithSize(4, 4))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {2, 2}: 1})
+	c := NewQuantumObject("C", gridDist(2, 2))
+	for _, obj := range []*QuantumObject{a, b, c} {
+		world.AddQuantumObject(obj)
+	}
+	world.MeasureInteraction(a, b)
+	if len(world.AuditLog()) != 0 {
+		t.Fatal("log should stay empty until enabled")
+	}
+
+	world.EnableAuditLog()
+	a.ResetWith(map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	b.ResetWith(map[[2]int]float64{{0, 0}: 1, {2, 2}: 1})
+	world.MeasureInteraction(a, b)
+	c.Collapse()
+	world.CollapseAll()
+
+	log := world.AuditLog()
+	if len(log) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(log))
+	}
+	m := log[0]
+	if m.Operation != "MeasureInteraction" || m.ObjectNames[0] != "A" || m.ObjectNames[1] != "B" {
+		t.Errorf("unexpected measurement event %+v", m)
+	}
+	if !m.Collapsed[0] || m.ResultCoords[0] != [2]int{0, 0} || m.EntropyBefore[0] != 1 || m.EntropyAfter[0] != 0 {
+		t.Errorf("measurement should record outcome and entropies, got %+v", m)
+	}
+	if log[1].Operation != "Collapse" || log[1].ResultCoords[0] != c.FinalCoord {
+		t.Errorf("unexpected collapse event %+v", log[1])
+	}
+	if log[2].Operation != "CollapseAll" || len(log[2].ObjectNames) != 3 {
+		t.Errorf("CollapseAll should list every object, got %+v", log[2])
+	}
+
+	log[0].Operation = "tampered"
+	if world.AuditLog()[0].Operation != "MeasureInteraction" {
+		t.Error("AuditLog should return a copy")
+	}
+
+	var buf bytes.Buffer
+	if err := world.ExportAuditJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []AuditEvent
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 3 {
+		t.Errorf("exported log should round-trip, got %d events, err %v", len(decoded), err)
+	}
+}
@@ -0,0 +1,114 @@
+package quantum
+
+import "slices"
+
+// Clone возвращает независимую копию мира: объекты копируются глубоко
+// (см. QuantumObject.Clone), запутывающие связи между ними переносятся
+// на копии, обработчики OnCollapse и настройки мира сохраняются.
+// Генераторы мира и объектов копия получает собственные, засеянные от
+// генераторов оригинала в момент клонирования, поэтому коллапсы в копии не
+// сдвигают последовательность случайных чисел оригинала.
+// Изменения копии не затрагивают оригинал и наоборот.
+func (w *World) Clone() *World {
+	c, _ := w.clone()
+	return c
+}
+
+// clone возвращает глубокую копию мира и соответствие исходных объектов копиям.
+// Запутывающие связи переносятся на копии; связи с объектами вне мира отбрасываются.
+func (w *World) clone() (*World, map[*QuantumObject]*QuantumObject) {
+	w.mu.RLock()
+	objs := slices.Clone(w.Objects)
+	c := &World{
+		Width:        w.Width,
+		Height:       w.Height,
+		Boundary:     w.Boundary,
+		Neighborhood: w.Neighborhood,
+		Evolution:    w.Evolution,
+		Decoherence:  w.Decoherence,
+		rng:          w.rng.fork(),
+		minProb:      w.minProb,
+		strict:       w.strict,
+		indexSize:    w.indexSize,
+		hooks:        slices.Clone(w.hooks),
+	}
+	if w.index != nil {
+		c.indexSize = w.index.BucketSize
+	}
+	w.mu.RUnlock()
+	remap := make(map[*QuantumObject]*QuantumObject, len(objs))
+	for _, obj := range objs {
+		cp := obj.Clone()
+		cp.world = c
+		cp.rng = cp.rng.fork()
+		remap[obj] = cp
+		c.Objects = append(c.Objects, cp)
+	}
+	for _, obj := range objs {
+		obj.mu.RLock()
+		for _, l := range obj.entangled {
+			if partner, ok := remap[l.partner]; ok {
+				remap[obj].entangled = append(remap[obj].entangled,
+					link{partner: partner, pairing: l.pairing, inverse: l.inverse})
+			}
+		}
+		obj.mu.RUnlock()
+	}
+	return c, remap
+}
+
+// Branch расщепляет мир на две независимые копии по исходу измерения obj:
+// в первой obj коллапсирует в наиболее вероятную клетку, во второй его
+// распределение сужается до всех остальных клеток (с перенормировкой).
+// Если других клеток нет, вторая ветвь равна nil; если obj не принадлежит
+// миру или уже коллапсирован, обе ветви равны nil.
+func (w *World) Branch(obj *QuantumObject) (*World, *World) {
+	if obj.isCollapsed() || !w.contains(obj) {
+		return nil, nil
+	}
+	probs := obj.probabilities()
+	if len(probs) == 0 {
+		return nil, nil
+	}
+	var best [2]int
+	bestP := -1.0
+	for _, c := range sortedCoords(probs) {
+		if probs[c] > bestP {
+			best, bestP = c, probs[c]
+		}
+	}
+
+	first, remap := w.clone()
+	remap[obj].pin(best, first.rng)
+
+	if len(probs) == 1 {
+		return first, nil
+	}
+	second, remap := w.clone()
+	rest := remap[obj]
+	delete(probs, best)
+	rest.ResetWith(probs)
+	rest.NormalizeDistribution()
+	return first, second
+}
+
+// BranchAll возвращает по одной копии мира на каждую клетку, где obj имеет
+// ненулевую вероятность; в каждой копии obj коллапсирован в свою клетку.
+// Ветви упорядочены по (x, y).
+func (w *World) BranchAll(obj *QuantumObject) []*World {
+	if obj.isCollapsed() || !w.contains(obj) {
+		return nil
+	}
+	probs := obj.probabilities()
+	branches := make([]*World, 0, len(probs))
+	for _, c := range sortedCoords(probs) {
+		b, remap := w.clone()
+		remap[obj].pin(c, b.rng)
+		branches = append(branches, b)
+	}
+	return branches
+}
+
+func (w *World) contains(obj *QuantumObject) bool {
+	return slices.Contains(w.objects(), obj)
+}
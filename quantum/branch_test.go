@@ -0,0 +1,97 @@
+package quantum
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBranch(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 3, {2, 2}: 1})
+	world.AddQuantumObject(obj)
+
+	likely, rest := world.Branch(obj)
+	if a := likely.Objects[0]; !a.IsCollapsed || a.FinalCoord != [2]int{1, 1} {
+		t.Errorf("first branch should collapse to the mode (1,1), got %v", a)
+	}
+	b := rest.Objects[0]
+	if b.IsCollapsed || len(b.CoordDist) != 2 || b.CoordDist[[2]int{0, 0}] != 0.5 {
+		t.Errorf("second branch should hold the renormalized remainder, got %v", b.CoordDist)
+	}
+	if obj.IsCollapsed || len(obj.CoordDist) != 3 {
+		t.Error("branching must not modify the original world")
+	}
+	b.Collapse()
+	if likely.Objects[0].FinalCoord != [2]int{1, 1} {
+		t.Error("branches must be independent")
+	}
+}
+
+func TestBranchAllKeepsEntanglement(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1, {2, 2}: 0})
+	b := NewQuantumObject("B", map[[2]int]float64{{4, 4}: 1, {3, 3}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.Entangle(a, b, mirror)
+
+	branches := world.BranchAll(a)
+	if len(branches) != 2 {
+		t.Fatalf("expected one branch per nonzero cell, got %d", len(branches))
+	}
+	for _, br := range branches {
+		ca, cb := br.Objects[0], br.Objects[1]
+		if !cb.IsCollapsed || cb.FinalCoord != mirror(ca.FinalCoord) {
+			t.Errorf("entangled partner should follow in each branch: %v %v", ca, cb)
+		}
+	}
+	if b.IsCollapsed {
+		t.Error("original partner must stay in superposition")
+	}
+}
+
+func TestWorldClone(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.Entangle(a, b, func(c [2]int) [2]int { return c })
+
+	clone := world.Clone()
+	if len(clone.Objects) != 2 || clone.Objects[0] == a || clone.Objects[1] == b {
+		t.Fatal("clone should hold its own copies of the objects")
+	}
+	ca, cb := clone.Objects[0], clone.Objects[1]
+	ca.Collapse()
+	if !cb.IsCollapsed || cb.FinalCoord != ca.FinalCoord {
+		t.Error("entanglement should be remapped onto the cloned objects")
+	}
+	ca.CoordDist[[2]int{2, 2}] = 5
+	if a.IsCollapsed || b.IsCollapsed || len(a.CoordDist) != 2 {
+		t.Errorf("original should stay in superposition and unchanged: %v %v", a, b)
+	}
+}
+
+func TestWorldCloneHasOwnRNG(t *testing.T) {
+	run := func(collapseClone bool) [][2]int {
+		world := NewWorld(WithSize(4, 4), WithRNG(rand.New(rand.NewSource(7))))
+		for _, name := range []string{"A", "B", "C"} {
+			world.AddQuantumObject(NewQuantumObject(name, UniformDistribution(4, 4)))
+		}
+		clone := world.Clone()
+		if collapseClone {
+			clone.CollapseAll()
+		}
+		world.CollapseAll()
+		var out [][2]int
+		for _, obj := range world.Objects {
+			out = append(out, obj.FinalCoord)
+		}
+		return out
+	}
+	if a, b := run(false), run(true); !slices.Equal(a, b) {
+		t.Errorf("collapsing the clone changed the original's outcomes: %v vs %v", a, b)
+	}
+}
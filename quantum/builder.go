@@ -0,0 +1,96 @@
+package quantum
+
+// DistributionBuilder пошагово строит распределение цепочкой вызовов:
+//
+//	dist := NewDistributionBuilder().
+//		Uniform(10, 10).
+//		AddGaussian(3, 3, 1.5, 2).
+//		Mask(inSquare).
+//		Build()
+//
+// Add-методы и Uniform добавляют нормированную компоненту с заданным весом,
+// как QuantumObject.AddComponent, так что веса задают доли смеси.
+// Гауссиана и кольцо строятся на сетке, заданной Uniform или Grid;
+// до этого сетка пуста и они ничего не добавляют.
+type DistributionBuilder struct {
+	width, height int
+	dist          map[[2]int]float64
+}
+
+// NewDistributionBuilder создаёт построитель с пустым распределением.
+func NewDistributionBuilder() *DistributionBuilder {
+	return &DistributionBuilder{dist: make(map[[2]int]float64)}
+}
+
+// Grid задаёт сетку width×height для последующих компонент.
+func (b *DistributionBuilder) Grid(width, height int) *DistributionBuilder {
+	b.width, b.height = width, height
+	return b
+}
+
+// Uniform задаёт сетку width×height и добавляет равномерную компоненту с весом 1.
+func (b *DistributionBuilder) Uniform(width, height int) *DistributionBuilder {
+	b.Grid(width, height)
+	return b.add(maskedUniform(width, height, nil), 1)
+}
+
+// AddGaussian добавляет гауссову компоненту с центром (cx, cy) и отклонением sigma.
+func (b *DistributionBuilder) AddGaussian(cx, cy int, sigma, weight float64) *DistributionBuilder {
+	return b.add(gaussianGrid(b.width, b.height, float64(cx), float64(cy), sigma, sigma), weight)
+}
+
+// AddRing добавляет кольцо радиуса r с полушириной полосы thickness
+// (см. NewRingQuantumObject).
+func (b *DistributionBuilder) AddRing(cx, cy, r, thickness int, weight float64) *DistributionBuilder {
+	return b.add(ringGrid(cx, cy, r, thickness, b.width, b.height), weight)
+}
+
+// Multiply поклеточно умножает распределение на other; клетки,
+// отсутствующие в other, обнуляются и удаляются.
+func (b *DistributionBuilder) Multiply(other map[[2]int]float64) *DistributionBuilder {
+	for c, p := range b.dist {
+		if q := p * other[c]; q > 0 {
+			b.dist[c] = q
+		} else {
+			delete(b.dist, c)
+		}
+	}
+	return b
+}
+
+// Mask оставляет только клетки, для которых fn возвращает true.
+func (b *DistributionBuilder) Mask(fn func([2]int) bool) *DistributionBuilder {
+	for c := range b.dist {
+		if !fn(c) {
+			delete(b.dist, c)
+		}
+	}
+	return b
+}
+
+// Normalize нормирует распределение на единицу; пустое остаётся пустым.
+func (b *DistributionBuilder) Normalize() *DistributionBuilder {
+	if total := SparseDistribution(b.dist).Total(); total > 0 {
+		for c, p := range b.dist {
+			b.dist[c] = p / total
+		}
+	}
+	return b
+}
+
+// Build возвращает копию построенного распределения; построитель можно
+// использовать дальше. Нормировка не выполняется, если не вызван Normalize.
+func (b *DistributionBuilder) Build() map[[2]int]float64 {
+	return copyDist(b.dist)
+}
+
+// add добавляет нормированную компоненту dist с весом weight;
+// неположительный вес и пустая компонента игнорируются.
+func (b *DistributionBuilder) add(dist map[[2]int]float64, weight float64) *DistributionBuilder {
+	total := SparseDistribution(dist).Total()
+	if weight <= 0 || total <= 0 {
+		return b
+	}
+	addScaled(b.dist, dist, weight/total)
+	return b
+}
@@ -0,0 +1,43 @@
+package quantum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistributionBuilder(t *testing.T) {
+	inSquare := func(c [2]int) bool { return c[0] < 5 && c[1] < 5 }
+	b := NewDistributionBuilder().Uniform(10, 10).AddGaussian(3, 3, 1.5, 2).Mask(inSquare)
+	dist := b.Build()
+	if len(dist) != 25 {
+		t.Fatalf("mask should keep the 5×5 square, got %d cells", len(dist))
+	}
+	if dist[[2]int{3, 3}] <= dist[[2]int{0, 4}] {
+		t.Errorf("Gaussian peak should dominate the uniform background: %v", dist)
+	}
+	if total := SparseDistribution(dist).Total(); total >= 3 {
+		t.Errorf("masked mixture should lose mass before Normalize, total %v", total)
+	}
+	if total := SparseDistribution(b.Normalize().Build()).Total(); math.Abs(total-1) > 1e-12 {
+		t.Errorf("Normalize should give total 1, got %v", total)
+	}
+
+	mixed := NewDistributionBuilder().Uniform(4, 4).AddGaussian(1, 1, 1, 1).Build()
+	want := NewMixtureQuantumObject("M", []MixtureComponent{
+		{UniformDistribution(4, 4), 1},
+		{GaussianDistribution(4, 4, 1, 1, 1), 1},
+	})
+	for c, p := range want.CoordDist {
+		if math.Abs(mixed[c]/2-p) > 1e-9 {
+			t.Errorf("builder mixture differs from NewMixtureQuantumObject at %v: %v vs %v", c, mixed[c]/2, p)
+		}
+	}
+
+	ring := NewDistributionBuilder().Grid(9, 9).AddRing(4, 4, 3, 1, 1).Multiply(map[[2]int]float64{{4, 7}: 2, {4, 4}: 5}).Build()
+	if len(ring) != 1 || ring[[2]int{4, 7}] <= 0 {
+		t.Errorf("Multiply should keep only cells present in both, got %v", ring)
+	}
+	if got := NewDistributionBuilder().AddGaussian(0, 0, 1, 1).Build(); len(got) != 0 {
+		t.Errorf("components without a grid should add nothing, got %v", got)
+	}
+}
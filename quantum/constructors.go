@@ -0,0 +1,239 @@
+package quantum
+
+import "math"
+
+// NewGaussianQuantumObject создаёт объект с гауссовым распределением на сетке
+// width×height: вес клетки exp(-((x-cx)² + (y-cy)²) / (2σ²)), затем нормировка.
+// Малые sigma (порядка 1) дают почти точечный источник в (cx, cy).
+func NewGaussianQuantumObject(name string, cx, cy int, sigma float64, width, height int) *QuantumObject {
+	return NewEllipticalGaussian(name, float64(cx), float64(cy), sigma, sigma, width, height)
+}
+
+// NewEllipticalGaussian — анизотропный вариант NewGaussianQuantumObject
+// с независимыми разбросами sigmaX и sigmaY по осям.
+// Неположительный разброс вырождает распределение по этой оси в ближайшую к центру клетку.
+func NewEllipticalGaussian(name string, cx, cy, sigmaX, sigmaY float64, width, height int) *QuantumObject {
+	q := NewQuantumObject(name, gaussianGrid(width, height, cx, cy, sigmaX, sigmaY))
+	q.normalizeLocked()
+	return q
+}
+
+// gaussianEpsilon — порог, ниже которого GaussianDistribution опускает клетки.
+const gaussianEpsilon = 1e-12
+
+// GaussianDistribution возвращает нормированное двумерное гауссово
+// распределение на сетке width×height с центром (cx, cy) и стандартным
+// отклонением sigma. Клетки с вероятностью ниже 1e-12 опускаются.
+func GaussianDistribution(width, height, cx, cy int, sigma float64) map[[2]int]float64 {
+	return EllipticalGaussianDistribution(width, height, cx, cy, sigma, sigma)
+}
+
+// EllipticalGaussianDistribution — GaussianDistribution с разными
+// отклонениями sigmaX и sigmaY по осям.
+func EllipticalGaussianDistribution(width, height, cx, cy int, sigmaX, sigmaY float64) map[[2]int]float64 {
+	dist := gaussianGrid(width, height, float64(cx), float64(cy), sigmaX, sigmaY)
+	total := SparseDistribution(dist).Total()
+	for c, w := range dist {
+		if p := w / total; p >= gaussianEpsilon {
+			dist[c] = p
+		} else {
+			delete(dist, c)
+		}
+	}
+	return dist
+}
+
+// gaussianGrid — ненормированные веса гауссианы по всем клеткам сетки.
+func gaussianGrid(width, height int, cx, cy, sigmaX, sigmaY float64) map[[2]int]float64 {
+	fx := gaussAxis(cx, sigmaX, width)
+	fy := gaussAxis(cy, sigmaY, height)
+	dist := make(map[[2]int]float64, width*height)
+	for x, wx := range fx {
+		for y, wy := range fy {
+			if w := wx * wy; w > 0 {
+				dist[[2]int{x, y}] = w
+			}
+		}
+	}
+	return dist
+}
+
+// gaussAxis — одномерные гауссовы множители по оси длины size.
+func gaussAxis(center, sigma float64, size int) []float64 {
+	f := make([]float64, size)
+	if sigma <= 0 {
+		if i := int(math.Round(center)); i >= 0 && i < size {
+			f[i] = 1
+		}
+		return f
+	}
+	for i := range f {
+		d := float64(i) - center
+		f[i] = math.Exp(-0.5 * d * d / (sigma * sigma))
+	}
+	return f
+}
+
+// UniformDistribution возвращает равномерное распределение по всем клеткам
+// сетки width×height.
+func UniformDistribution(width, height int) map[[2]int]float64 {
+	return maskedUniform(width, height, nil)
+}
+
+// MaxEntropyObject создаёт объект с наибольшей возможной энтропией в границах
+// мира — равномерный по всем клеткам. Это естественное начальное состояние
+// объекта, о положении которого ничего не известно. Необязательная маска
+// ограничивает распределение клетками, для которых она возвращает true;
+// внутри них распределение по-прежнему равномерно.
+func MaxEntropyObject(name string, world *World, mask ...func([2]int) bool) *QuantumObject {
+	var keep func([2]int) bool
+	if len(mask) > 0 {
+		keep = mask[0]
+	}
+	return NewQuantumObject(name, maskedUniform(world.Width, world.Height, keep))
+}
+
+// maskedUniform — равномерное распределение по клеткам сетки, прошедшим keep;
+// nil пропускает все клетки.
+func maskedUniform(width, height int, keep func([2]int) bool) map[[2]int]float64 {
+	dist := make(map[[2]int]float64, width*height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			if c := [2]int{x, y}; keep == nil || keep(c) {
+				dist[c] = 1
+			}
+		}
+	}
+	p := 1 / float64(len(dist))
+	for c := range dist {
+		dist[c] = p
+	}
+	return dist
+}
+
+// NewBoltzmannQuantumObject создаёт объект в тепловом равновесии на сетке
+// width×height: вес клетки exp(-energy(c)/temperature), затем нормировка.
+// Энергии отсчитываются от минимальной, поэтому большие энергии не
+// обнуляют все веса. При temperature → 0 распределение стремится к основному
+// состоянию (клеткам с наименьшей энергией), при temperature → ∞ —
+// к равномерному. Неположительная temperature даёт сразу предел T → 0.
+func NewBoltzmannQuantumObject(name string, energy func([2]int) float64, temperature float64, width, height int) *QuantumObject {
+	energies := make(map[[2]int]float64, width*height)
+	ground := math.Inf(1)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			c := [2]int{x, y}
+			energies[c] = energy(c)
+			ground = min(ground, energies[c])
+		}
+	}
+	dist := make(map[[2]int]float64, len(energies))
+	for c, e := range energies {
+		switch {
+		case temperature > 0:
+			dist[c] = math.Exp(-(e - ground) / temperature)
+		case e == ground:
+			dist[c] = 1
+		}
+	}
+	q := NewQuantumObject(name, dist)
+	q.normalizeLocked()
+	return q
+}
+
+// MixtureComponent — компонента смеси: распределение и его относительный вес.
+type MixtureComponent struct {
+	Distribution map[[2]int]float64
+	Weight       float64
+}
+
+// NewMixtureQuantumObject создаёт объект, который с относительными весами
+// Weight находится в одном из распределений-компонент:
+// CoordDist[c] = Σ wᵢ·distᵢ[c], затем нормировка. Каждая компонента перед
+// смешиванием нормируется, так что вес не зависит от её масштаба.
+// Одна компонента с GaussianDistribution повторяет NewGaussianQuantumObject.
+func NewMixtureQuantumObject(name string, components []MixtureComponent) *QuantumObject {
+	q := NewQuantumObject(name, make(map[[2]int]float64))
+	for _, comp := range components {
+		q.AddComponent(comp.Distribution, comp.Weight)
+	}
+	q.NormalizeDistribution()
+	return q
+}
+
+// AddComponent добавляет к распределению нормированную компоненту dist с весом
+// weight и возвращает q, что позволяет строить смесь цепочкой вызовов.
+// Имеющееся распределение сохраняет свою полную массу как вес, поэтому
+// у нормированного объекта AddComponent(d, 1) даёт смесь поровну.
+// Неположительный вес, пустая компонента и коллапсированный объект
+// оставляют распределение без изменений.
+func (q *QuantumObject) AddComponent(dist map[[2]int]float64, weight float64) *QuantumObject {
+	total := SparseDistribution(dist).Total()
+	if weight <= 0 || total <= 0 {
+		return q
+	}
+	defer q.supportChanged()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.IsCollapsed {
+		return q
+	}
+	q.sparseLocked()
+	if q.CoordDist == nil {
+		q.CoordDist = make(map[[2]int]float64, len(dist))
+	}
+	addScaled(q.CoordDist, dist, weight/total)
+	return q
+}
+
+// addScaled прибавляет к dst положительные веса src, умноженные на scale.
+func addScaled(dst, src map[[2]int]float64, scale float64) {
+	for c, p := range src {
+		if p > 0 {
+			dst[c] += scale * p
+		}
+	}
+}
+
+// ringSubsamples — число подвыборок по каждой оси при оценке перекрытия клетки с кольцом.
+const ringSubsamples = 8
+
+// NewRingQuantumObject создаёт объект на кольце вокруг (cx, cy): клетка целиком
+// внутри полосы |√((x-cx)² + (y-cy)²) - radius| <= width получает вес 1,
+// клетка на краю полосы — долю своей площади, попавшую в полосу, остальные — 0.
+// Распределение строится на сетке gridW×gridH и нормируется.
+func NewRingQuantumObject(name string, cx, cy, radius, width int, gridW, gridH int) *QuantumObject {
+	q := NewQuantumObject(name, ringGrid(cx, cy, radius, width, gridW, gridH))
+	q.normalizeLocked()
+	return q
+}
+
+// ringGrid — ненормированные веса кольца NewRingQuantumObject по клеткам сетки.
+func ringGrid(cx, cy, radius, width int, gridW, gridH int) map[[2]int]float64 {
+	inner, outer := float64(radius-width), float64(radius+width)
+	inBand := func(x, y float64) bool {
+		r := math.Hypot(x-float64(cx), y-float64(cy))
+		return r >= inner && r <= outer
+	}
+	const n = ringSubsamples
+	dist := make(map[[2]int]float64)
+	for x := 0; x < gridW; x++ {
+		for y := 0; y < gridH; y++ {
+			// клетка (x,y) занимает квадрат [x-½, x+½]×[y-½, y+½]
+			hits := 0
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					sx := float64(x) - 0.5 + (float64(i)+0.5)/n
+					sy := float64(y) - 0.5 + (float64(j)+0.5)/n
+					if inBand(sx, sy) {
+						hits++
+					}
+				}
+			}
+			if hits > 0 {
+				dist[[2]int{x, y}] = float64(hits) / (n * n)
+			}
+		}
+	}
+	return dist
+}
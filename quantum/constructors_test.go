@@ -0,0 +1,147 @@
+package quantum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewGaussianQuantumObject(t *testing.T) {
+	obj := NewGaussianQuantumObject("G", 4, 5, 1.5, 10, 10)
+	if len(obj.CoordDist) != 100 {
+		t.Fatalf("expected every grid cell, got %d", len(obj.CoordDist))
+	}
+	if s := SparseDistribution(obj.CoordDist).Total(); math.Abs(s-1) > 1e-9 {
+		t.Errorf("distribution should be normalized, total %f", s)
+	}
+	if x, y := obj.ExpectedPosition(); math.Abs(x-4) > 0.01 || math.Abs(y-5) > 0.01 {
+		t.Errorf("mean should sit at the center, got (%f, %f)", x, y)
+	}
+	peak := obj.CoordDist[[2]int{4, 5}]
+	for c, p := range obj.CoordDist {
+		if p > peak {
+			t.Errorf("cell %v outweighs the center", c)
+		}
+	}
+}
+
+func TestNewEllipticalGaussian(t *testing.T) {
+	obj := NewEllipticalGaussian("E", 10, 10, 4, 1, 21, 21)
+	sx, sy := obj.StdDev()
+	if sx <= 2*sy {
+		t.Errorf("x spread should dominate: σx=%f σy=%f", sx, sy)
+	}
+
+	point := NewEllipticalGaussian("P", 3, 3, 0, 0, 5, 5)
+	if len(point.CoordDist) != 1 || point.CoordDist[[2]int{3, 3}] != 1 {
+		t.Errorf("zero sigma should give a point source, got %v", point.CoordDist)
+	}
+}
+
+func TestNewRingQuantumObject(t *testing.T) {
+	obj := NewRingQuantumObject("R", 10, 10, 6, 1, 21, 21)
+	if obj.ProbabilityAt(10, 10) != 0 {
+		t.Error("center should be outside the ring")
+	}
+	onRing := obj.ProbabilityAt(16, 10)
+	if onRing == 0 || math.Abs(obj.ProbabilityAt(10, 4)-onRing) > 1e-12 {
+		t.Errorf("cells on the ring should share full weight, got %f and %f",
+			onRing, obj.ProbabilityAt(10, 4))
+	}
+	edge := obj.ProbabilityAt(17, 10)
+	if edge <= 0 || edge >= onRing {
+		t.Errorf("edge cell should get fractional weight, got %f (full %f)", edge, onRing)
+	}
+	if x, y := obj.ExpectedPosition(); math.Abs(x-10) > 1e-9 || math.Abs(y-10) > 1e-9 {
+		t.Errorf("ring should be centered, mean (%f, %f)", x, y)
+	}
+}
+
+func TestGaussianDistribution(t *testing.T) {
+	dist := GaussianDistribution(50, 50, 25, 25, 1)
+	if len(dist) >= 50*50 {
+		t.Errorf("negligible cells should be omitted, got %d cells", len(dist))
+	}
+	if s := SparseDistribution(dist).Total(); math.Abs(s-1) > 1e-9 {
+		t.Errorf("distribution should be normalized, total %f", s)
+	}
+
+	wide := NewQuantumObject("W", EllipticalGaussianDistribution(30, 30, 15, 15, 5, 1))
+	if sx, sy := wide.StdDev(); math.Abs(sx-5) > 0.1 || math.Abs(sy-1) > 0.1 {
+		t.Errorf("per-axis sigma should set the spread, got σx=%f σy=%f", sx, sy)
+	}
+}
+
+func TestMaxEntropyObject(t *testing.T) {
+	if s := SparseDistribution(UniformDistribution(4, 3)).Total(); math.Abs(s-1) > 1e-12 {
+		t.Errorf("uniform distribution should be normalized, total %f", s)
+	}
+
+	world := NewWorld(WithSize(8, 4))
+	obj := MaxEntropyObject("U", world)
+	if h := obj.Entropy(); math.Abs(h-5) > 1e-9 {
+		t.Errorf("entropy over 32 cells should be 5 bits, got %f", h)
+	}
+	peaked := NewGaussianQuantumObject("G", 4, 2, 1, 8, 4)
+	if peaked.Entropy() >= obj.Entropy() {
+		t.Error("no distribution on the grid should exceed the uniform entropy")
+	}
+
+	left := MaxEntropyObject("L", world, func(c [2]int) bool { return c[0] < 2 })
+	if len(left.CoordDist) != 8 || math.Abs(left.Entropy()-3) > 1e-9 {
+		t.Errorf("masked object should be uniform over 8 cells, got %d cells, H=%f",
+			len(left.CoordDist), left.Entropy())
+	}
+}
+
+func TestNewBoltzmannQuantumObject(t *testing.T) {
+	well := func(c [2]int) float64 {
+		dx, dy := float64(c[0]-3), float64(c[1]-2)
+		return 1000 + dx*dx + dy*dy
+	}
+	warm := NewBoltzmannQuantumObject("B", well, 2, 7, 5)
+	if p, q := warm.ProbabilityAt(3, 2), warm.ProbabilityAt(4, 2); math.Abs(q/p-math.Exp(-0.5)) > 1e-12 {
+		t.Errorf("neighbouring cells should follow the Boltzmann ratio, got %f", q/p)
+	}
+
+	cold := NewBoltzmannQuantumObject("C", well, 1e-3, 7, 5)
+	if cold.ProbabilityAt(3, 2) < 1-1e-9 {
+		t.Errorf("low temperature should settle in the ground state, got %f", cold.ProbabilityAt(3, 2))
+	}
+	frozen := NewBoltzmannQuantumObject("F", well, 0, 7, 5)
+	if len(frozen.CoordDist) != 1 || frozen.ProbabilityAt(3, 2) != 1 {
+		t.Errorf("zero temperature should give the ground state only: %v", frozen.CoordDist)
+	}
+
+	hot := NewBoltzmannQuantumObject("H", well, 1e9, 7, 5)
+	if math.Abs(hot.Entropy()-math.Log2(35)) > 1e-6 {
+		t.Errorf("high temperature should approach the uniform entropy, got %f", hot.Entropy())
+	}
+}
+
+func TestNewMixtureQuantumObject(t *testing.T) {
+	left := GaussianDistribution(20, 10, 4, 5, 1)
+	right := GaussianDistribution(20, 10, 15, 5, 1)
+	obj := NewMixtureQuantumObject("M", []MixtureComponent{
+		{Distribution: left, Weight: 3},
+		{Distribution: right, Weight: 1},
+	})
+	if s := SparseDistribution(obj.CoordDist).Total(); math.Abs(s-1) > 1e-12 {
+		t.Errorf("mixture should be normalized, total %f", s)
+	}
+	if r := obj.ProbabilityAt(4, 5) / obj.ProbabilityAt(15, 5); math.Abs(r-3) > 1e-9 {
+		t.Errorf("peak ratio should follow the weights, got %f", r)
+	}
+
+	single := NewMixtureQuantumObject("S", []MixtureComponent{{Distribution: left, Weight: 7}})
+	gauss := NewGaussianQuantumObject("G", 4, 5, 1, 20, 10)
+	if math.Abs(single.ProbabilityAt(4, 5)-gauss.ProbabilityAt(4, 5)) > 1e-9 {
+		t.Error("a single component should reproduce the Gaussian constructor")
+	}
+
+	built := NewQuantumObject("B", nil).
+		AddComponent(map[[2]int]float64{{0, 0}: 10}, 1).
+		AddComponent(map[[2]int]float64{{1, 1}: 1, {2, 2}: 1}, 1)
+	if p := built.ProbabilityAt(0, 0); math.Abs(p-0.5) > 1e-12 {
+		t.Errorf("components should be normalized before weighting, got %f", p)
+	}
+}
@@ -0,0 +1,111 @@
+package quantum
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ExportCSV записывает нормированное распределение строками x,y,probability
+// с заголовком, упорядоченными по (x, y); клетки с нулевой вероятностью
+// пропускаются. Коллапсированный объект даёт одну строку с вероятностью 1.
+func (q *QuantumObject) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"x", "y", "probability"}); err != nil {
+		return err
+	}
+	if err := writeCSVRows(cw, q); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CSVSumWarning — предупреждение ImportCSV: вероятности в файле дают в сумме
+// Sum, а не 1. Распределение при этом всё равно импортировано и нормировано.
+type CSVSumWarning struct {
+	Name string
+	Sum  float64
+}
+
+func (e *CSVSumWarning) Error() string {
+	return fmt.Sprintf("csv: %s: probabilities sum to %g, normalized", e.Name, e.Sum)
+}
+
+// ImportCSV читает распределение в формате ExportCSV (заголовок x,y,probability)
+// и заменяет им распределение объекта, возвращая его в суперпозицию.
+// Отрицательные или нечисловые значения — ошибка, объект при этом не меняется.
+// Если вероятности не дают в сумме 1, распределение нормируется и
+// возвращается *CSVSumWarning; его можно отличить от ошибки через errors.As.
+func (q *QuantumObject) ImportCSV(r io.Reader) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("csv: missing header")
+	}
+	if h := records[0]; len(h) != 3 || h[0] != "x" || h[1] != "y" || h[2] != "probability" {
+		return fmt.Errorf("csv: unexpected header %q, want x,y,probability", h)
+	}
+	dist := make(map[[2]int]float64, len(records)-1)
+	sum := 0.0
+	for i, rec := range records[1:] {
+		line := i + 2
+		x, errX := strconv.Atoi(rec[0])
+		y, errY := strconv.Atoi(rec[1])
+		p, errP := strconv.ParseFloat(rec[2], 64)
+		if err := cmp.Or(errX, errY, errP); err != nil {
+			return fmt.Errorf("csv: line %d: %w", line, err)
+		}
+		if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+			return fmt.Errorf("csv: line %d: invalid probability %v", line, p)
+		}
+		dist[[2]int{x, y}] += p
+		sum += p
+	}
+	q.ResetWith(dist)
+	q.NormalizeDistribution()
+	if math.Abs(sum-1) > csvSumTolerance {
+		return &CSVSumWarning{Name: q.Name, Sum: sum}
+	}
+	return nil
+}
+
+// csvSumTolerance — допустимое отклонение суммы вероятностей от 1 в ImportCSV.
+const csvSumTolerance = 1e-6
+
+// ExportCSV записывает распределения всех объектов мира строками
+// name,x,y,probability в порядке Objects.
+func (w *World) ExportCSV(out io.Writer) error {
+	cw := csv.NewWriter(out)
+	if err := cw.Write([]string{"name", "x", "y", "probability"}); err != nil {
+		return err
+	}
+	for _, obj := range w.objects() {
+		if err := writeCSVRows(cw, obj, obj.Name); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSVRows пишет строки распределения q, начиная каждую с prefix.
+func writeCSVRows(cw *csv.Writer, q *QuantumObject, prefix ...string) error {
+	probs := q.probabilities()
+	for _, c := range sortedCoords(probs) {
+		row := append(append([]string(nil), prefix...),
+			strconv.Itoa(c[0]),
+			strconv.Itoa(c[1]),
+			strconv.FormatFloat(probs[c], 'g', -1, 64),
+		)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package quantum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExportCSV(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{2, 0}: 1, {0, 3}: 2, {0, 1}: 1})
+	var buf bytes.Buffer
+	if err := obj.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "x,y,probability\n0,1,0.25\n0,3,0.5\n2,0,0.25\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestWorldExportCSV(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", gridDist(3, 3))
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1, {4, 4}: 3})
+	b.Collapse()
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+
+	var buf bytes.Buffer
+	if err := world.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums := make(map[string]float64)
+	rows := make(map[string]int)
+	for _, rec := range records[1:] {
+		p, err := strconv.ParseFloat(rec[3], 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sums[rec[0]] += p
+		rows[rec[0]]++
+	}
+	for name, s := range sums {
+		if math.Abs(s-1) > 1e-9 {
+			t.Errorf("%s probabilities sum to %f", name, s)
+		}
+	}
+	if rows["A"] != 9 || rows["B"] != 1 {
+		t.Errorf("unexpected row counts %v", rows)
+	}
+}
+
+func TestImportCSVRoundTrip(t *testing.T) {
+	src := NewQuantumObject("A", map[[2]int]float64{{2, 0}: 1, {0, 3}: 2, {0, 1}: 1})
+	var buf bytes.Buffer
+	if err := src.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dst := NewQuantumObject("B", map[[2]int]float64{{9, 9}: 1})
+	dst.Collapse()
+	if err := dst.ImportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if dst.IsCollapsed || len(dst.CoordDist) != 3 || dst.CoordDist[[2]int{0, 3}] != 0.5 {
+		t.Errorf("import should replace the distribution, got %v", dst.CoordDist)
+	}
+}
+
+func TestImportCSVValidation(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1})
+	for _, input := range []string{
+		"",
+		"a,b,c\n0,0,1\n",
+		"x,y,probability\n0,0,-0.5\n",
+		"x,y,probability\n0,zero,1\n",
+	} {
+		if err := obj.ImportCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("input %q should be rejected", input)
+		}
+	}
+	if obj.CoordDist[[2]int{1, 1}] != 1 {
+		t.Error("failed import must not modify the object")
+	}
+
+	var warn *CSVSumWarning
+	err := obj.ImportCSV(strings.NewReader("x,y,probability\n0,0,2\n1,0,2\n"))
+	if !errors.As(err, &warn) || warn.Sum != 4 {
+		t.Fatalf("unnormalized input should only warn, got %v", err)
+	}
+	if obj.CoordDist[[2]int{0, 0}] != 0.5 {
+		t.Errorf("imported distribution should be normalized, got %v", obj.CoordDist)
+	}
+}
@@ -0,0 +1,164 @@
+package quantum
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// DensityMatrix — матрица плотности ρ в базисе клеток: ключ {строка, столбец}
+// — пара клеток, значение — элемент ρ. Отсутствующие элементы равны нулю.
+// В отличие от распределения вероятностей матрица плотности различает
+// когерентную суперпозицию (ненулевые внедиагональные элементы) и
+// статистическую смесь тех же клеток.
+type DensityMatrix map[[2][2]int]complex128
+
+// NewDensityMatrix строит чистое состояние |ψ⟩⟨ψ| объекта с вещественными
+// амплитудами √p по его нормированному распределению; коллапсированный
+// объект даёт |FinalCoord⟩⟨FinalCoord|. Матрица содержит N² элементов,
+// где N — размер носителя.
+func NewDensityMatrix(q *QuantumObject) DensityMatrix {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.IsCollapsed {
+		return DensityMatrix{{q.FinalCoord, q.FinalCoord}: 1}
+	}
+	dist := q.coordsLocked()
+	amps := make(map[[2]int]complex128, len(dist))
+	if total := q.total(); total > 0 {
+		for c, p := range dist {
+			if p > 0 {
+				amps[c] = complex(math.Sqrt(p/total), 0)
+			}
+		}
+	}
+	return outer(amps)
+}
+
+// DensityMatrix строит чистое состояние |ψ⟩⟨ψ| по нормированным амплитудам
+// объекта с сохранением фаз.
+func (q *QuantumAmplitudeObject) DensityMatrix() DensityMatrix {
+	if q.IsCollapsed {
+		return DensityMatrix{{q.FinalCoord, q.FinalCoord}: 1}
+	}
+	total := 0.0
+	for _, a := range q.AmplitudeDist {
+		total += born(a)
+	}
+	amps := make(map[[2]int]complex128, len(q.AmplitudeDist))
+	if total > 0 {
+		scale := complex(1/math.Sqrt(total), 0)
+		for c, a := range q.AmplitudeDist {
+			if a != 0 {
+				amps[c] = a * scale
+			}
+		}
+	}
+	return outer(amps)
+}
+
+// outer возвращает внешнее произведение ρ_ij = a_i·conj(a_j).
+func outer(amps map[[2]int]complex128) DensityMatrix {
+	rho := make(DensityMatrix, len(amps)*len(amps))
+	for ci, ai := range amps {
+		for cj, aj := range amps {
+			rho[[2][2]int{ci, cj}] = ai * cmplx.Conj(aj)
+		}
+	}
+	return rho
+}
+
+// MixDensity возвращает статистическую смесь Σ wᵢ·ρᵢ с весами, нормированными
+// на их сумму. Лишние веса или состояния отбрасываются; неположительные веса
+// пропускаются.
+func MixDensity(states []DensityMatrix, weights []float64) DensityMatrix {
+	n := min(len(states), len(weights))
+	total := 0.0
+	for _, w := range weights[:n] {
+		total += max(w, 0)
+	}
+	mix := make(DensityMatrix)
+	if total <= 0 {
+		return mix
+	}
+	for i, rho := range states[:n] {
+		if weights[i] <= 0 {
+			continue
+		}
+		scale := complex(weights[i]/total, 0)
+		for k, v := range rho {
+			mix[k] += scale * v
+		}
+	}
+	return mix
+}
+
+// At возвращает элемент ρ для пары клеток.
+func (rho DensityMatrix) At(row, col [2]int) complex128 {
+	return rho[[2][2]int{row, col}]
+}
+
+// Trace возвращает след матрицы — сумму диагональных элементов
+// (для эрмитовой матрицы он вещественен).
+func (rho DensityMatrix) Trace() float64 {
+	tr := 0.0
+	for k, v := range rho {
+		if k[0] == k[1] {
+			tr += real(v)
+		}
+	}
+	return tr
+}
+
+// Purity возвращает чистоту Tr(ρ²)/Tr(ρ)²: 1 для чистого состояния и 1/N
+// для равномерной смеси N клеток. Для пустой матрицы возвращает 0.
+func (rho DensityMatrix) Purity() float64 {
+	tr := rho.Trace()
+	if tr <= 0 {
+		return 0
+	}
+	sum := 0.0
+	for k, v := range rho {
+		sum += real(v * rho[[2][2]int{k[1], k[0]}])
+	}
+	return sum / (tr * tr)
+}
+
+// Probabilities возвращает нормированную диагональ ρ — распределение,
+// по которому происходит коллапс. Клетки с нулевой вероятностью опускаются.
+func (rho DensityMatrix) Probabilities() map[[2]int]float64 {
+	out := make(map[[2]int]float64)
+	tr := rho.Trace()
+	if tr <= 0 {
+		return out
+	}
+	for k, v := range rho {
+		if p := real(v); k[0] == k[1] && p > 0 {
+			out[k[0]] = p / tr
+		}
+	}
+	return out
+}
+
+// Dephase возвращает матрицу с внедиагональными элементами, умноженными
+// на 1-rate: rate = 0 оставляет состояние, rate = 1 превращает его в
+// классическую смесь с той же диагональю. rate ограничивается отрезком [0, 1].
+func (rho DensityMatrix) Dephase(rate float64) DensityMatrix {
+	keep := complex(1-min(max(rate, 0), 1), 0)
+	out := make(DensityMatrix, len(rho))
+	for k, v := range rho {
+		if k[0] != k[1] {
+			v *= keep
+			if cmplx.Abs(v) < cancelledAmplitude {
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// ToQuantumObject возвращает объект с распределением Probabilities.
+// Когерентности при этом теряются.
+func (rho DensityMatrix) ToQuantumObject(name string) *QuantumObject {
+	return NewQuantumObject(name, rho.Probabilities())
+}
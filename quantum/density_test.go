@@ -0,0 +1,64 @@
+package quantum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDensityMatrixPureState(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 0}: 3})
+	rho := NewDensityMatrix(obj)
+	if len(rho) != 4 {
+		t.Fatalf("pure state over 2 cells should have 4 elements, got %d", len(rho))
+	}
+	if tr := rho.Trace(); math.Abs(tr-1) > 1e-12 {
+		t.Errorf("Trace = %v, want 1", tr)
+	}
+	if p := rho.Purity(); math.Abs(p-1) > 1e-12 {
+		t.Errorf("pure state Purity = %v, want 1", p)
+	}
+	if c := rho.At([2]int{0, 0}, [2]int{1, 0}); math.Abs(real(c)-math.Sqrt(0.25*0.75)) > 1e-12 {
+		t.Errorf("coherence = %v, want √(p0·p1)", c)
+	}
+	probs := rho.Probabilities()
+	if math.Abs(probs[[2]int{1, 0}]-0.75) > 1e-12 || len(probs) != 2 {
+		t.Errorf("diagonal should reproduce the distribution, got %v", probs)
+	}
+
+	obj.CollapseWith(0)
+	if rho := NewDensityMatrix(obj); len(rho) != 1 || rho.At(obj.FinalCoord, obj.FinalCoord) != 1 {
+		t.Errorf("collapsed object should give |c⟩⟨c|, got %v", rho)
+	}
+}
+
+func TestDensityMatrixAmplitudePhases(t *testing.T) {
+	amp := NewQuantumAmplitudeObject("A", map[[2]int]complex128{{0, 0}: 1, {1, 0}: -1})
+	rho := amp.DensityMatrix()
+	if c := rho.At([2]int{0, 0}, [2]int{1, 0}); math.Abs(real(c)+0.5) > 1e-12 {
+		t.Errorf("opposite phases should give a negative coherence, got %v", c)
+	}
+	if c := rho.At([2]int{1, 0}, [2]int{0, 0}); c != rho.At([2]int{0, 0}, [2]int{1, 0}) {
+		t.Errorf("real coherences should be symmetric, got %v", rho)
+	}
+}
+
+func TestDensityMatrixMixtureAndDephasing(t *testing.T) {
+	a := NewDensityMatrix(NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1}))
+	b := NewDensityMatrix(NewQuantumObject("B", map[[2]int]float64{{1, 0}: 1}))
+	mix := MixDensity([]DensityMatrix{a, b}, []float64{1, 1})
+	if p := mix.Purity(); math.Abs(p-0.5) > 1e-12 {
+		t.Errorf("even mixture of two cells should have purity 1/2, got %v", p)
+	}
+
+	pure := NewDensityMatrix(NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1, {1, 0}: 1}))
+	if d := pure.Dephase(0); math.Abs(d.Purity()-1) > 1e-12 {
+		t.Errorf("zero dephasing should keep the state pure, got %v", d.Purity())
+	}
+	dephased := pure.Dephase(1)
+	if math.Abs(dephased.Purity()-mix.Purity()) > 1e-12 || len(dephased) != 2 {
+		t.Errorf("full dephasing should give the classical mixture, got %v", dephased)
+	}
+	if got := dephased.ToQuantumObject("D").CoordDist; got[[2]int{0, 0}] != 0.5 || got[[2]int{1, 0}] != 0.5 {
+		t.Errorf("dephasing should keep the diagonal, got %v", got)
+	}
+}
@@ -0,0 +1,200 @@
+package quantum
+
+import "slices"
+
+// Distribution — представление весов координат объекта.
+// Веса не обязаны быть нормированы.
+type Distribution interface {
+	Weight(x, y int) float64
+	Set(x, y int, w float64)
+	Range(fn func(c [2]int, w float64) bool) // обход ненулевых весов; false останавливает обход
+	Total() float64
+}
+
+// SparseDistribution — распределение на карте: хранит только ненулевые клетки.
+// Совпадает по устройству с QuantumObject.CoordDist.
+type SparseDistribution map[[2]int]float64
+
+func (d SparseDistribution) Weight(x, y int) float64 { return d[[2]int{x, y}] }
+
+// Set задаёт вес клетки; нулевой вес удаляет её из карты.
+func (d SparseDistribution) Set(x, y int, w float64) {
+	if w == 0 {
+		delete(d, [2]int{x, y})
+		return
+	}
+	d[[2]int{x, y}] = w
+}
+
+// Range обходит клетки в порядке sortedCoords.
+func (d SparseDistribution) Range(fn func(c [2]int, w float64) bool) {
+	for _, c := range sortedCoords(d) {
+		if w := d[c]; w != 0 && !fn(c, w) {
+			return
+		}
+	}
+}
+
+func (d SparseDistribution) Total() float64 {
+	total := 0.0
+	for _, w := range d {
+		total += w
+	}
+	return total
+}
+
+// DenseDistribution — распределение на сетке Width×Height, веса хранятся
+// построчно в срезе: клетка (x,y) лежит в Weights[y*Width+x].
+// Экономнее карты для плотных распределений вроде равномерного.
+type DenseDistribution struct {
+	Width   int
+	Height  int
+	Weights []float64
+}
+
+// NewDenseDistribution создаёт нулевое плотное распределение width×height.
+func NewDenseDistribution(width, height int) *DenseDistribution {
+	return &DenseDistribution{Width: width, Height: height, Weights: make([]float64, width*height)}
+}
+
+func (d *DenseDistribution) inside(x, y int) bool {
+	return x >= 0 && x < d.Width && y >= 0 && y < d.Height
+}
+
+// Weight возвращает вес клетки; вне сетки вес нулевой.
+func (d *DenseDistribution) Weight(x, y int) float64 {
+	if !d.inside(x, y) {
+		return 0
+	}
+	return d.Weights[y*d.Width+x]
+}
+
+// Set задаёт вес клетки; клетки вне сетки игнорируются.
+func (d *DenseDistribution) Set(x, y int, w float64) {
+	if d.inside(x, y) {
+		d.Weights[y*d.Width+x] = w
+	}
+}
+
+// Range обходит ненулевые клетки в том же порядке, что и SparseDistribution:
+// по x, затем по y.
+func (d *DenseDistribution) Range(fn func(c [2]int, w float64) bool) {
+	for x := 0; x < d.Width; x++ {
+		for y := 0; y < d.Height; y++ {
+			if w := d.Weights[y*d.Width+x]; w != 0 && !fn([2]int{x, y}, w) {
+				return
+			}
+		}
+	}
+}
+
+func (d *DenseDistribution) Total() float64 {
+	total := 0.0
+	for _, w := range d.Weights {
+		total += w
+	}
+	return total
+}
+
+// ToSparse переводит любое распределение в карту.
+func ToSparse(d Distribution) SparseDistribution {
+	if s, ok := d.(SparseDistribution); ok {
+		return s
+	}
+	out := make(SparseDistribution)
+	d.Range(func(c [2]int, w float64) bool {
+		out[c] = w
+		return true
+	})
+	return out
+}
+
+// ToDense переводит распределение в плотное width×height; клетки вне сетки отбрасываются.
+func ToDense(d Distribution, width, height int) *DenseDistribution {
+	out := NewDenseDistribution(width, height)
+	d.Range(func(c [2]int, w float64) bool {
+		out.Set(c[0], c[1], w)
+		return true
+	})
+	return out
+}
+
+// Distribution возвращает копию распределения объекта в том представлении,
+// в котором оно хранится: *DenseDistribution для плотного, иначе
+// SparseDistribution. Изменения копии объекту не видны.
+func (q *QuantumObject) Distribution() Distribution {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if d, ok := q.denseLocked().(*DenseDistribution); ok {
+		return d.clone()
+	}
+	return SparseDistribution(copyDist(q.CoordDist))
+}
+
+// SetDistribution заменяет распределение объекта копией d. Плотное
+// распределение хранится как есть — CoordDist при этом равен nil, и веса
+// читаются через Distribution, ProbabilityAt и т. п.; первое изменение
+// распределения (нормировка, измерение, коллапс, эволюция) переводит его
+// в CoordDist. Остальные представления сразу копируются в CoordDist.
+func (q *QuantumObject) SetDistribution(d Distribution) {
+	defer q.supportChanged()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.setDistributionLocked(d)
+}
+
+func (q *QuantumObject) setDistributionLocked(d Distribution) {
+	if dense, ok := d.(*DenseDistribution); ok {
+		q.dist, q.CoordDist = dense.clone(), nil
+		return
+	}
+	q.dist, q.CoordDist = nil, copyDist(ToSparse(d))
+}
+
+// NewQuantumObjectFrom создаёт объект с распределением в любом представлении
+// (см. SetDistribution).
+func NewQuantumObjectFrom(name string, d Distribution) *QuantumObject {
+	q := NewQuantumObject(name, nil)
+	q.setDistributionLocked(d)
+	return q
+}
+
+// denseLocked возвращает плотное хранилище, если веса лежат в нём.
+// CoordDist, присвоенный напрямую, имеет приоритет.
+func (q *QuantumObject) denseLocked() Distribution {
+	if q.CoordDist != nil {
+		return nil
+	}
+	return q.dist
+}
+
+// sparseLocked переводит плотное хранилище в CoordDist. Вызывается под
+// блокировкой объекта на запись перед любым изменением или прямым
+// чтением CoordDist.
+func (q *QuantumObject) sparseLocked() {
+	if d := q.denseLocked(); d != nil {
+		q.CoordDist = ToSparse(d)
+	}
+	q.dist = nil
+}
+
+// coordsLocked возвращает веса объекта картой только для чтения: CoordDist
+// или временную копию плотного хранилища. Достаточно блокировки на чтение.
+func (q *QuantumObject) coordsLocked() map[[2]int]float64 {
+	if d := q.denseLocked(); d != nil {
+		return ToSparse(d)
+	}
+	return q.CoordDist
+}
+
+func (d *DenseDistribution) clone() *DenseDistribution {
+	return &DenseDistribution{Width: d.Width, Height: d.Height, Weights: slices.Clone(d.Weights)}
+}
+
+// cloneStored копирует плотное хранилище объекта; nil остаётся nil.
+func cloneStored(d Distribution) Distribution {
+	if dense, ok := d.(*DenseDistribution); ok {
+		return dense.clone()
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package quantum
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDenseAndSparseAgree(t *testing.T) {
+	dense := NewDenseDistribution(3, 2)
+	sparse := make(SparseDistribution)
+	for _, d := range []Distribution{dense, sparse} {
+		d.Set(0, 1, 2)
+		d.Set(2, 0, 1)
+		d.Set(5, 5, 7) // вне плотной сетки
+		d.Set(5, 5, 0)
+	}
+	if dense.Total() != 3 || sparse.Total() != 3 {
+		t.Fatalf("totals differ: dense %f, sparse %f", dense.Total(), sparse.Total())
+	}
+	if dense.Weight(0, 1) != sparse.Weight(0, 1) || dense.Weight(-1, 0) != 0 {
+		t.Error("weights should match and be zero outside the grid")
+	}
+
+	var visited [][2]int
+	dense.Range(func(c [2]int, w float64) bool {
+		visited = append(visited, c)
+		return true
+	})
+	if len(visited) != 2 || visited[0] != [2]int{0, 1} || visited[1] != [2]int{2, 0} {
+		t.Errorf("dense range should follow x-then-y order, got %v", visited)
+	}
+}
+
+func TestObjectFromDenseDistribution(t *testing.T) {
+	dense := NewDenseDistribution(4, 4)
+	for i := range dense.Weights {
+		dense.Weights[i] = 1
+	}
+	a := NewQuantumObjectFrom("A", dense)
+	b := NewQuantumObject("B", ToSparse(dense))
+	a.SetRand(rand.New(rand.NewSource(3)))
+	b.SetRand(rand.New(rand.NewSource(3)))
+	a.Collapse()
+	b.Collapse()
+	if a.FinalCoord != b.FinalCoord {
+		t.Errorf("same weights and seed should collapse identically: %v vs %v", a, b)
+	}
+
+	a.SetDistribution(ToDense(SparseDistribution{{1, 1}: 1}, 4, 4))
+	if a.Distribution().Weight(1, 1) != 1 || a.CoordDist != nil {
+		t.Errorf("dense distribution should be stored as is, got CoordDist %v", a.CoordDist)
+	}
+}
+
+func TestDenseDistributionStorage(t *testing.T) {
+	dense := NewDenseDistribution(3, 3)
+	dense.Set(0, 0, 1)
+	dense.Set(2, 1, 3)
+	q := NewQuantumObjectFrom("Q", dense)
+	dense.Set(0, 0, 100)
+
+	got, ok := q.Distribution().(*DenseDistribution)
+	if !ok {
+		t.Fatalf("Distribution() = %T, want *DenseDistribution", q.Distribution())
+	}
+	got.Set(1, 1, 5)
+	if q.ProbabilityAt(0, 0) != 0.25 || q.ProbabilityAt(1, 1) != 0 {
+		t.Errorf("object should not share memory with the caller: P(0,0)=%v P(1,1)=%v",
+			q.ProbabilityAt(0, 0), q.ProbabilityAt(1, 1))
+	}
+
+	q.NormalizeDistribution()
+	if _, ok := q.Distribution().(SparseDistribution); !ok || q.CoordDist[[2]int{2, 1}] != 0.75 {
+		t.Errorf("mutation should move weights to CoordDist, got %v", q.CoordDist)
+	}
+
+	s := q.Distribution().(SparseDistribution)
+	s.Set(2, 1, 0)
+	if q.CoordDist[[2]int{2, 1}] != 0.75 {
+		t.Error("sparse Distribution() should be a copy")
+	}
+
+	c := NewQuantumObjectFrom("C", dense)
+	c.SetRand(rand.New(rand.NewSource(1)))
+	c.Collapse()
+	if fc := c.FinalCoord; !c.IsCollapsed || (fc != [2]int{0, 0} && fc != [2]int{2, 1}) {
+		t.Errorf("dense object collapsed to %v", fc)
+	}
+}
@@ -0,0 +1,89 @@
+package quantum
+
+import "slices"
+
+// link — запутывающая связь объекта с партнёром. Правило pairing задаёт
+// координату партнёра по координате исходного объекта; inverse отмечает
+// связь, хранящуюся на стороне образа.
+type link struct {
+	partner *QuantumObject
+	pairing func([2]int) [2]int
+	inverse bool
+}
+
+// Entangle запутывает obj1 и obj2: после коллапса obj1 в c объект obj2
+// обязан оказаться в pairing(c). Коллапс obj2 в c2 сужает распределение obj1
+// до прообраза {c : pairing(c) = c2} и коллапсирует его.
+// Если один из объектов уже коллапсирован, партнёр фиксируется сразу.
+func (w *World) Entangle(obj1, obj2 *QuantumObject, pairing func([2]int) [2]int) {
+	obj1.addLink(link{partner: obj2, pairing: pairing})
+	obj2.addLink(link{partner: obj1, pairing: pairing, inverse: true})
+	switch {
+	case obj1.isCollapsed():
+		obj1.propagate(w.rng)
+	case obj2.isCollapsed():
+		obj2.propagate(w.rng)
+	}
+}
+
+func (q *QuantumObject) addLink(l link) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entangled = append(q.entangled, l)
+}
+
+// Disentangle разрывает все связи между obj1 и obj2.
+func (w *World) Disentangle(obj1, obj2 *QuantumObject) {
+	obj1.unlink(obj2)
+	obj2.unlink(obj1)
+}
+
+func (q *QuantumObject) unlink(partner *QuantumObject) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	kept := q.entangled[:0]
+	for _, l := range q.entangled {
+		if l.partner != partner {
+			kept = append(kept, l)
+		}
+	}
+	q.entangled = kept
+}
+
+// propagate передаёт результат коллапса q его партнёрам. Коллапсированные
+// партнёры пропускаются, поэтому циклы связей не приводят к зацикливанию.
+// Вызывается без удержания блокировки q.
+func (q *QuantumObject) propagate(fallback *lockedRand) {
+	q.mu.RLock()
+	coord := q.FinalCoord
+	links := slices.Clone(q.entangled)
+	q.mu.RUnlock()
+
+	for _, l := range links {
+		p := l.partner
+		if !l.inverse {
+			p.pin(l.pairing(coord), fallback)
+			continue
+		}
+		p.mu.Lock()
+		if p.IsCollapsed {
+			p.mu.Unlock()
+			continue
+		}
+		preimage := make(map[[2]int]float64)
+		for c, w := range p.coordsLocked() {
+			if w > 0 && l.pairing(c) == coord {
+				preimage[c] = w
+			}
+		}
+		settled := false
+		if len(preimage) > 0 {
+			p.dist, p.CoordDist = nil, preimage
+			settled = p.collapseLocked(fallback)
+		}
+		p.mu.Unlock()
+		if settled {
+			p.settled(fallback)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package quantum
+
+import "testing"
+
+func mirror(c [2]int) [2]int { return [2]int{4 - c[0], 4 - c[1]} }
+
+func TestEntangleDeterminesPartner(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 2}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{2, 2}: 1})
+	world.Entangle(a, b, mirror)
+	a.Collapse()
+	if !b.IsCollapsed || b.FinalCoord != mirror(a.FinalCoord) {
+		t.Errorf("B should collapse to %v, got %v (collapsed=%v)", mirror(a.FinalCoord), b.FinalCoord, b.IsCollapsed)
+	}
+
+	c := NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1, {1, 2}: 1})
+	d := NewQuantumObject("D", map[[2]int]float64{{3, 2}: 1})
+	world.Entangle(c, d, mirror)
+	d.Collapse()
+	if !c.IsCollapsed || c.FinalCoord != [2]int{1, 2} {
+		t.Errorf("C should be pinned to the preimage (1,2), got %v", c.FinalCoord)
+	}
+}
+
+func TestEntangleCollapsedAndCycles(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1})
+	a.Collapse()
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {3, 3}: 1})
+	world.Entangle(a, b, mirror)
+	if !b.IsCollapsed || b.FinalCoord != [2]int{3, 3} {
+		t.Errorf("linking to a collapsed object should pin the partner, got %v", b.FinalCoord)
+	}
+
+	x := NewQuantumObject("X", map[[2]int]float64{{0, 0}: 1, {4, 4}: 1})
+	y := NewQuantumObject("Y", map[[2]int]float64{{0, 0}: 1, {4, 4}: 1})
+	world.Entangle(x, y, mirror)
+	world.Entangle(y, x, mirror)
+	x.Collapse()
+	if !y.IsCollapsed || y.FinalCoord != mirror(x.FinalCoord) {
+		t.Error("cyclic entanglement should still resolve both objects")
+	}
+
+	p := NewQuantumObject("P", map[[2]int]float64{{0, 0}: 1, {4, 4}: 1})
+	q := NewQuantumObject("Q", map[[2]int]float64{{0, 0}: 1, {4, 4}: 1})
+	world.Entangle(p, q, mirror)
+	world.Disentangle(p, q)
+	p.Collapse()
+	if q.IsCollapsed {
+		t.Error("disentangled partner should stay in superposition")
+	}
+}
@@ -0,0 +1,86 @@
+package quantum
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// EventKind — вид события в журнале мира.
+type EventKind int
+
+const (
+	EventCreate   EventKind = iota // объект добавлен в мир
+	EventMeasure                   // взаимодействие объектов
+	EventCollapse                  // объект коллапсировал
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventCreate:
+		return "create"
+	case EventMeasure:
+		return "measure"
+	case EventCollapse:
+		return "collapse"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event — запись журнала событий. Для EventMeasure Names перечисляет
+// участников, а Support — число клеток совместного распределения
+// (0, если общих точек не нашлось); для EventCollapse Coord — итоговая клетка.
+type Event struct {
+	Kind    EventKind
+	Time    time.Time
+	Names   []string
+	Support int
+	Coord   [2]int
+}
+
+// EnableEventLog включает или выключает журнал событий: добавление объектов,
+// взаимодействия и коллапсы. В отличие от AuditLog журнал не вычисляет
+// энтропии и записывает каждый коллапс отдельно, в том числе вызванный
+// запутанностью. Выключение не очищает уже записанное.
+func (w *World) EnableEventLog(enabled bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.eventing = enabled
+}
+
+// History возвращает копию журнала событий в порядке записи. Это журнал
+// EnableEventLog; стек снимков для Undo (EnableHistory, HistoryLen) — отдельный.
+func (w *World) History() []Event {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.events)
+}
+
+// ClearHistory очищает журнал событий; стек снимков для Undo не затрагивается.
+func (w *World) ClearHistory() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events = nil
+}
+
+// logEvent добавляет событие, если журнал включён. Не вызывается под
+// блокировкой объекта: порядок захвата — мир, затем объект.
+func (w *World) logEvent(ev Event) {
+	w.mu.RLock()
+	enabled := w.eventing
+	w.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.logEventLocked(ev)
+}
+
+func (w *World) logEventLocked(ev Event) {
+	if !w.eventing {
+		return
+	}
+	ev.Time = time.Now()
+	w.events = append(w.events, ev)
+}
@@ -0,0 +1,53 @@
+package quantum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEventLog(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	world.AddQuantumObject(NewQuantumObject("quiet", map[[2]int]float64{{0, 0}: 1}))
+	if len(world.History()) != 0 {
+		t.Fatal("events should not be recorded while the log is disabled")
+	}
+
+	world.EnableEventLog(true)
+	a := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1, {2, 2}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1})
+	c := NewQuantumObject("C", map[[2]int]float64{{0, 2}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.AddQuantumObject(c)
+	world.MeasureInteraction(a, c)
+	world.MeasureInteraction(a, b)
+
+	events := world.History()
+	var kinds []EventKind
+	for _, ev := range events {
+		kinds = append(kinds, ev.Kind)
+		if ev.Time.IsZero() {
+			t.Errorf("%v event has no timestamp", ev.Kind)
+		}
+	}
+	want := []EventKind{EventCreate, EventCreate, EventCreate, EventMeasure, EventMeasure, EventCollapse, EventCollapse}
+	if !slices.Equal(kinds, want) {
+		t.Fatalf("event kinds = %v, want %v", kinds, want)
+	}
+	if events[3].Support != 0 || !slices.Equal(events[3].Names, []string{"A", "C"}) {
+		t.Errorf("disjoint measurement should log zero support: %+v", events[3])
+	}
+	if events[4].Support != 1 {
+		t.Errorf("measurement support = %d, want 1", events[4].Support)
+	}
+	if events[5].Names[0] != "A" || events[5].Coord != [2]int{1, 1} {
+		t.Errorf("unexpected collapse event: %+v", events[5])
+	}
+
+	world.ClearHistory()
+	world.EnableEventLog(false)
+	c.Collapse()
+	if len(world.History()) != 0 {
+		t.Error("cleared and disabled log should stay empty")
+	}
+}
@@ -0,0 +1,247 @@
+package quantum
+
+import "math"
+
+// EvolutionFunc изменяет распределение несколлапсированного объекта за шаг dt.
+// Размеры, топологию и окрестность мира правило получает через obj.World().
+type EvolutionFunc func(obj *QuantumObject, dt float64)
+
+// WorldSnapshot — состояние мира после очередного шага Simulate.
+type WorldSnapshot struct {
+	Step    int
+	Time    float64
+	Objects []*QuantumObject // независимые копии объектов мира
+}
+
+// GaussianDiffusion — правило эволюции по умолчанию: свёртка распределения
+// с гауссовым ядром ширины σ = √dt (ядро обрезается на 3σ). Доля, уходящая
+// за край в режиме Bounded, перераспределяется между допустимыми клетками ядра,
+// так что полная вероятность сохраняется. Объект вне мира диффундирует
+// на неограниченной плоскости.
+func GaussianDiffusion(obj *QuantumObject, dt float64) {
+	if dt <= 0 {
+		return
+	}
+	sigma := math.Sqrt(dt)
+	r := int(math.Ceil(3 * sigma))
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	move := func(c, d [2]int) ([2]int, bool) {
+		return [2]int{c[0] + d[0], c[1] + d[1]}, true
+	}
+	if obj.world != nil {
+		move = obj.world.Wrap
+	}
+	obj.normalizeLocked()
+	newDist := make(map[[2]int]float64)
+	for coord, prob := range obj.CoordDist {
+		if prob <= 0 {
+			continue
+		}
+		targets := make(map[[2]int]float64)
+		sum := 0.0
+		for dx := -r; dx <= r; dx++ {
+			for dy := -r; dy <= r; dy++ {
+				c, ok := move(coord, [2]int{dx, dy})
+				if !ok {
+					continue
+				}
+				k := math.Exp(-float64(dx*dx+dy*dy) / (2 * sigma * sigma))
+				targets[c] += k
+				sum += k
+			}
+		}
+		if sum == 0 {
+			newDist[coord] += prob
+			continue
+		}
+		for c, k := range targets {
+			newDist[c] += prob * k / sum
+		}
+	}
+	obj.CoordDist = newDist
+}
+
+// Diffuse выполняет один шаг диффузии по соседям: каждая клетка передаёт долю
+// rate своей вероятности поровну соседям — четырём ортогональным, а для объекта
+// в мире с окрестностью Moore восьми. Для объекта, добавленного в мир, соседи
+// вычисляются с учётом граничного режима мира, а доля, уходящая за край
+// в режиме Bounded, остаётся в клетке.
+// Коллапсированный объект не диффундирует, пока не будет сброшен.
+func (q *QuantumObject) Diffuse(rate float64) {
+	defer q.supportChanged()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.IsCollapsed || rate <= 0 {
+		return
+	}
+	rate = min(rate, 1)
+	neighbour := func(c, d [2]int) ([2]int, bool) {
+		return [2]int{c[0] + d[0], c[1] + d[1]}, true
+	}
+	offsets := VonNeumann.offsets()
+	if q.world != nil {
+		neighbour = q.world.Wrap
+		offsets = q.world.Neighborhood.offsets()
+	}
+	q.normalizeLocked()
+	newDist := make(map[[2]int]float64, len(q.CoordDist))
+	for coord, prob := range q.CoordDist {
+		if prob <= 0 {
+			continue
+		}
+		newDist[coord] += prob * (1 - rate)
+		share := prob * rate / float64(len(offsets))
+		for _, d := range offsets {
+			if c, ok := neighbour(coord, d); ok {
+				newDist[c] += share
+			} else {
+				newDist[coord] += share
+			}
+		}
+	}
+	q.CoordDist = newDist
+}
+
+// Shift переносит всё распределение объекта на (dx, dy), моделируя
+// детерминированное движение; FinalCoord коллапсированного объекта
+// сдвигается так же. Сетка — размеры мира, в который объект добавлен,
+// с граничным режимом boundary: в Toroidal и Reflecting масса приводится
+// внутрь сетки и сохраняется, в Bounded вышедшая за край масса отбрасывается,
+// а остаток нормируется. Если за край ушла бы вся масса (или FinalCoord),
+// объект не меняется. Объект вне мира сдвигается без ограничений.
+func (q *QuantumObject) Shift(dx, dy int, boundary BoundaryMode) {
+	defer q.supportChanged()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d := [2]int{dx, dy}
+	move := func(c [2]int) ([2]int, bool) {
+		return [2]int{c[0] + dx, c[1] + dy}, true
+	}
+	if q.world != nil {
+		grid := &World{Width: q.world.Width, Height: q.world.Height, Boundary: boundary}
+		move = func(c [2]int) ([2]int, bool) { return grid.Wrap(c, d) }
+	}
+	final, ok := move(q.FinalCoord)
+	if q.IsCollapsed && !ok {
+		return
+	}
+	q.sparseLocked()
+	newDist := make(map[[2]int]float64, len(q.CoordDist))
+	dropped := false
+	for c, p := range q.CoordDist {
+		if n, ok := move(c); ok {
+			newDist[n] += p
+		} else if p > 0 {
+			dropped = true
+		}
+	}
+	if len(newDist) == 0 {
+		return
+	}
+	q.CoordDist = newDist
+	if q.IsCollapsed {
+		q.FinalCoord = final
+	}
+	if dropped {
+		q.normalizeLocked()
+	}
+}
+
+// NeighbourDiffusion возвращает правило эволюции для World.Evolution, которое
+// на шаге dt выполняет Diffuse с долей 1-(1-rate)^dt; при dt = 1 это ровно
+// один шаг Diffuse(rate) на каждый вызов World.Step.
+func NeighbourDiffusion(rate float64) EvolutionFunc {
+	return func(obj *QuantumObject, dt float64) {
+		if dt <= 0 {
+			return
+		}
+		obj.Diffuse(1 - math.Pow(1-min(rate, 1), dt))
+	}
+}
+
+// DecoherenceModel описывает взаимодействие объекта с окружением за время dt.
+type DecoherenceModel interface {
+	Decohere(obj *QuantumObject, dt float64)
+}
+
+// UniformDecoherence подмешивает к распределению равномерное по сетке
+// Width×Height: p' = (1-r)·p + r/(Width·Height), где r = 1-(1-Rate)^dt.
+// При Rate = 0 распределение не меняется, при Rate = 1 сразу становится равномерным.
+// Относительное изменение тем больше, чем сильнее локализован объект.
+type UniformDecoherence struct {
+	Width, Height int
+	Rate          float64 // доля равномерной примеси за единицу времени, [0,1]
+}
+
+// Decohere применяет модель к объекту; коллапсированные объекты не изменяются.
+func (d UniformDecoherence) Decohere(obj *QuantumObject, dt float64) {
+	n := d.Width * d.Height
+	if n <= 0 || d.Rate <= 0 || dt <= 0 {
+		return
+	}
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	if obj.IsCollapsed {
+		return
+	}
+	r := 1 - math.Pow(1-min(d.Rate, 1), dt)
+	obj.normalizeLocked()
+	newDist := make(map[[2]int]float64, n)
+	for c, p := range obj.CoordDist {
+		newDist[c] = (1 - r) * p
+	}
+	share := r / float64(n)
+	for x := 0; x < d.Width; x++ {
+		for y := 0; y < d.Height; y++ {
+			newDist[[2]int{x, y}] += share
+		}
+	}
+	obj.CoordDist = newDist
+	obj.normalizeLocked()
+}
+
+// ApplyDecoherence выполняет один шаг равномерной декогеренции с долей rate
+// для всех несколлапсированных объектов мира.
+func (w *World) ApplyDecoherence(rate float64) {
+	model := UniformDecoherence{Width: w.Width, Height: w.Height, Rate: rate}
+	for _, obj := range w.objects() {
+		model.Decohere(obj, 1)
+	}
+	w.invalidateIndex()
+}
+
+// Step продвигает мир на dt: к каждому несколлапсированному объекту применяется
+// w.Evolution (или GaussianDiffusion, если правило не задано), а затем
+// w.Decoherence, если модель задана. Коллапсированные объекты не изменяются.
+func (w *World) Step(dt float64) {
+	evolve := w.Evolution
+	if evolve == nil {
+		evolve = GaussianDiffusion
+	}
+	for _, obj := range w.objects() {
+		if obj.isCollapsed() {
+			continue
+		}
+		evolve(obj, dt)
+		if w.Decoherence != nil {
+			w.Decoherence.Decohere(obj, dt)
+		}
+	}
+	w.invalidateIndex()
+}
+
+// Simulate выполняет steps шагов Step(dt) и возвращает снимок мира после каждого.
+// При steps <= 0 мир не меняется и возвращается пустой срез.
+func (w *World) Simulate(steps int, dt float64) []WorldSnapshot {
+	snapshots := make([]WorldSnapshot, 0, max(steps, 0))
+	for i := 1; i <= steps; i++ {
+		w.Step(dt)
+		objs := w.objects()
+		for j, obj := range objs {
+			objs[j] = obj.Clone()
+		}
+		snapshots = append(snapshots, WorldSnapshot{Step: i, Time: float64(i) * dt, Objects: objs})
+	}
+	return snapshots
+}
@@ -0,0 +1,160 @@
+package quantum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStepDiffusesAndConservesMass(t *testing.T) {
+	world := NewWorld(WithSize(9, 9))
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	frozen := NewQuantumObject("B", map[[2]int]float64{{4, 4}: 1})
+	frozen.Collapse()
+	world.AddQuantumObject(obj)
+	world.AddQuantumObject(frozen)
+
+	snaps := world.Simulate(3, 0.5)
+	if len(snaps) != 3 || snaps[2].Step != 3 || snaps[2].Time != 1.5 {
+		t.Fatalf("unexpected snapshots: %+v", snaps)
+	}
+	if len(obj.CoordDist) <= 1 {
+		t.Error("point source should spread after stepping")
+	}
+	if math.Abs(obj.total()-1) > 1e-9 {
+		t.Errorf("probability not conserved at the corner, total=%f", obj.total())
+	}
+	if len(frozen.CoordDist) != 1 {
+		t.Error("collapsed objects must not evolve")
+	}
+	if len(snaps[0].Objects[0].CoordDist) >= len(obj.CoordDist) {
+		t.Error("snapshots should be independent copies of earlier states")
+	}
+
+	before := len(obj.CoordDist)
+	if snaps := world.Simulate(-1, 0.5); len(snaps) != 0 || len(obj.CoordDist) != before {
+		t.Errorf("negative step count should do nothing, got %d snapshots", len(snaps))
+	}
+}
+
+func TestStepCustomEvolution(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	obj := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1})
+	world.AddQuantumObject(obj)
+	calls := 0
+	world.Evolution = func(o *QuantumObject, dt float64) {
+		calls++
+		if o.World() != world {
+			t.Error("rule should reach the world through the object")
+		}
+		if dt != 0.25 {
+			t.Errorf("dt should be passed through, got %f", dt)
+		}
+	}
+	world.Step(0.25)
+	if calls != 1 {
+		t.Errorf("custom evolution should run once, ran %d times", calls)
+	}
+}
+
+func TestApplyDecoherence(t *testing.T) {
+	world := NewWorld(WithSize(4, 4))
+	obj := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1})
+	world.AddQuantumObject(obj)
+
+	world.ApplyDecoherence(0)
+	if len(obj.CoordDist) != 1 {
+		t.Error("rate 0 should leave the distribution unchanged")
+	}
+	world.ApplyDecoherence(0.5)
+	if p := obj.CoordDist[[2]int{1, 1}]; math.Abs(p-(0.5+0.5/16)) > 1e-12 {
+		t.Errorf("unexpected peak after partial decoherence: %f", p)
+	}
+	world.ApplyDecoherence(1)
+	if h := obj.Entropy(); math.Abs(h-4) > 1e-9 {
+		t.Errorf("rate 1 should flatten to maximum entropy, got %f", h)
+	}
+}
+
+func TestDiffuseRespectsBoundary(t *testing.T) {
+	bounded := NewWorld(WithSize(5, 5))
+	corner := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	bounded.AddQuantumObject(corner)
+	corner.Diffuse(0.4)
+	if p := corner.ProbabilityAt(0, 0); math.Abs(p-0.8) > 1e-12 {
+		t.Errorf("blocked shares should stay in the corner, got %f", p)
+	}
+
+	torus := NewWorld(WithSize(5, 5))
+	torus.Boundary = Toroidal
+	wrapped := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1})
+	torus.AddQuantumObject(wrapped)
+	wrapped.Diffuse(0.4)
+	if p := wrapped.ProbabilityAt(4, 0); math.Abs(p-0.1) > 1e-12 {
+		t.Errorf("toroidal diffusion should wrap around, got %f", p)
+	}
+
+	wrapped.Collapse()
+	wrapped.Diffuse(0.4)
+	if len(wrapped.CoordDist) != 1 {
+		t.Error("collapsed object must not diffuse")
+	}
+}
+
+func TestShift(t *testing.T) {
+	world := NewWorld(WithSize(4, 4))
+	dist := map[[2]int]float64{{0, 0}: 0.25, {3, 1}: 0.25, {2, 3}: 0.5}
+
+	torus := NewQuantumObject("T", copyDist(dist))
+	world.AddQuantumObject(torus)
+	torus.Shift(3, -5, Toroidal)
+	if total := SparseDistribution(torus.CoordDist).Total(); math.Abs(total-1) > 1e-12 {
+		t.Errorf("toroidal shift should conserve mass, got %v", total)
+	}
+	want := map[[2]int]float64{{3, 3}: 0.25, {2, 0}: 0.25, {1, 2}: 0.5}
+	for c, p := range want {
+		if torus.CoordDist[c] != p {
+			t.Errorf("toroidal shift: cell %v = %v, want %v", c, torus.CoordDist[c], p)
+		}
+	}
+
+	bounded := NewQuantumObject("B", copyDist(dist))
+	world.AddQuantumObject(bounded)
+	bounded.Shift(1, 0, Bounded)
+	if len(bounded.CoordDist) != 2 || math.Abs(bounded.ProbabilityAt(3, 3)-2.0/3) > 1e-12 {
+		t.Errorf("bounded shift should drop the edge cell and renormalize, got %v", bounded.CoordDist)
+	}
+	bounded.Shift(10, 0, Bounded)
+	if len(bounded.CoordDist) != 2 {
+		t.Errorf("shift moving all mass off the grid should be a no-op, got %v", bounded.CoordDist)
+	}
+
+	collapsed := NewQuantumObject("C", map[[2]int]float64{{3, 3}: 1})
+	world.AddQuantumObject(collapsed)
+	collapsed.Collapse()
+	collapsed.Shift(1, 1, Toroidal)
+	if collapsed.FinalCoord != [2]int{0, 0} || collapsed.CoordDist[[2]int{0, 0}] != 1 {
+		t.Errorf("collapsed object should move with its distribution, got %v", collapsed)
+	}
+}
+
+func TestStepWithNeighbourDiffusion(t *testing.T) {
+	world := NewWorld(WithSize(7, 7))
+	world.Evolution = NeighbourDiffusion(0.5)
+	obj := NewQuantumObject("A", map[[2]int]float64{{3, 3}: 1})
+	world.AddQuantumObject(obj)
+	prev := obj.Entropy()
+	for i := 0; i < 5; i++ {
+		world.Step(1)
+		if h := obj.Entropy(); h <= prev {
+			t.Fatalf("entropy should grow while spreading: %f -> %f", prev, h)
+		}
+		prev = obj.Entropy()
+	}
+	total := 0.0
+	for _, p := range obj.CoordDist {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("diffusion should conserve mass, total %f", total)
+	}
+}
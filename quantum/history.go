@@ -0,0 +1,146 @@
+package quantum
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+)
+
+// ErrNoHistory возвращается Undo, когда стек снимков пуст.
+var ErrNoHistory = errors.New("no history to undo")
+
+// historyEntry — снимок мира перед изменяющей операцией: размеры и топология,
+// состав Objects, глубокие копии состояний объектов и их запутывающие связи
+// в том же порядке.
+type historyEntry struct {
+	width, height int
+	boundary      BoundaryMode
+	neighborhood  Neighborhood
+	objects       []*QuantumObject
+	states        []*QuantumObject
+	links         [][]link
+}
+
+// EnableHistory включает запись снимков перед MeasureInteraction (и его
+// вариантами), CollapseAll, CollapseAllParallel и Collapse/CollapseWith
+// объектов мира. Хранится не больше maxDepth последних снимков;
+// maxDepth <= 0 выключает запись и очищает стек.
+func (w *World) EnableHistory(maxDepth int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.historyDepth = max(maxDepth, 0)
+	if len(w.history) > w.historyDepth {
+		w.history = w.history[len(w.history)-w.historyDepth:]
+	}
+	if w.historyDepth == 0 {
+		w.history = nil
+	}
+}
+
+// HistoryLen возвращает текущую глубину стека снимков.
+func (w *World) HistoryLen() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.history)
+}
+
+// Undo восстанавливает состояние мира из последнего снимка и удаляет его:
+// размеры, Boundary, Neighborhood, состав Objects, распределения и
+// запутывающие связи. Объекты восстанавливаются на месте, поэтому указатели
+// на них остаются действительными; удалённые после снимка объекты снова
+// привязываются к миру, а добавленные после него — отвязываются, как при
+// RemoveQuantumObject. Обработчики OnCollapse не откатываются.
+func (w *World) Undo() error {
+	w.mu.Lock()
+	if len(w.history) == 0 {
+		w.mu.Unlock()
+		return ErrNoHistory
+	}
+	entry := w.history[len(w.history)-1]
+	w.history = w.history[:len(w.history)-1]
+	var dropped []*QuantumObject
+	for _, obj := range w.Objects {
+		if !slices.Contains(entry.objects, obj) {
+			dropped = append(dropped, obj)
+		}
+	}
+	w.Width, w.Height = entry.width, entry.height
+	w.Boundary, w.Neighborhood = entry.boundary, entry.neighborhood
+	w.Objects = slices.Clone(entry.objects)
+	w.names = nil
+	w.invalidateIndexLocked()
+	w.mu.Unlock()
+
+	for _, obj := range dropped {
+		obj.mu.Lock()
+		links := obj.entangled
+		obj.entangled = nil
+		if obj.world == w {
+			obj.world = nil
+		}
+		obj.mu.Unlock()
+		for _, l := range links {
+			l.partner.unlink(obj)
+		}
+	}
+	for i, obj := range entry.objects {
+		w.attach(obj)
+		state := entry.states[i]
+		obj.mu.Lock()
+		obj.entangled = slices.Clone(entry.links[i])
+		obj.CoordDist = copyDist(state.CoordDist)
+		obj.dist = cloneStored(state.dist)
+		obj.IsCollapsed = state.IsCollapsed
+		obj.FinalCoord = state.FinalCoord
+		obj.prior = copyDist(state.prior)
+		obj.mu.Unlock()
+	}
+	return nil
+}
+
+// ExportHistory записывает стек снимков JSON-массивом от старого к новому;
+// каждый снимок имеет формат World.MarshalJSON и может быть прочитан в World.
+func (w *World) ExportHistory(out io.Writer) error {
+	w.mu.RLock()
+	snapshots := make([]worldJSON, len(w.history))
+	for i, entry := range w.history {
+		snapshots[i] = worldJSON{Width: entry.width, Height: entry.height, Objects: entry.states}
+	}
+	w.mu.RUnlock()
+	return json.NewEncoder(out).Encode(snapshots)
+}
+
+// record сохраняет снимок мира, если история включена.
+func (w *World) record() {
+	w.mu.RLock()
+	enabled := w.historyDepth > 0
+	w.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	objs := w.objects()
+	entry := historyEntry{
+		objects: objs,
+		states:  make([]*QuantumObject, len(objs)),
+		links:   make([][]link, len(objs)),
+	}
+	for i, obj := range objs {
+		entry.states[i] = obj.Clone()
+		obj.mu.RLock()
+		entry.links[i] = slices.Clone(obj.entangled)
+		obj.mu.RUnlock()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.historyDepth == 0 {
+		return
+	}
+	entry.width, entry.height = w.Width, w.Height
+	entry.boundary, entry.neighborhood = w.Boundary, w.Neighborhood
+	w.history = append(w.history, entry)
+	if len(w.history) > w.historyDepth {
+		w.history = slices.Delete(w.history, 0, len(w.history)-w.historyDepth)
+	}
+}
@@ -0,0 +1,107 @@
+package quantum
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestUndo(t *testing.T) {
+	world := NewWorld(WithSize(4, 4))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {2, 2}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	if err := world.Undo(); err != ErrNoHistory {
+		t.Errorf("empty history should report ErrNoHistory, got %v", err)
+	}
+
+	world.EnableHistory(2)
+	world.MeasureInteraction(a, b)
+	if !a.IsCollapsed || world.HistoryLen() != 1 {
+		t.Fatalf("measurement should be recorded, history %d", world.HistoryLen())
+	}
+	if err := world.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if a.IsCollapsed || b.IsCollapsed || len(a.CoordDist) != 2 || len(b.CoordDist) != 2 {
+		t.Errorf("undo should restore superpositions, got %v %v", a.CoordDist, b.CoordDist)
+	}
+
+	a.Collapse()
+	b.Collapse()
+	world.AddQuantumObject(NewQuantumObject("C", gridDist(2, 2)))
+	world.CollapseAll()
+	if world.HistoryLen() != 2 {
+		t.Errorf("history should be capped at maxDepth, got %d", world.HistoryLen())
+	}
+	world.Undo()
+	if world.Objects[2].IsCollapsed || !b.IsCollapsed {
+		t.Error("undo should step back over CollapseAll only")
+	}
+	world.Undo()
+	if !a.IsCollapsed || b.IsCollapsed || len(world.Objects) != 2 {
+		t.Error("second undo should restore the state before B collapsed")
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	world.AddQuantumObject(NewQuantumObject("A", gridDist(3, 3)))
+	world.EnableHistory(5)
+	world.CollapseAll()
+
+	var buf bytes.Buffer
+	if err := world.ExportHistory(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var snapshots []World
+	if err := json.Unmarshal(buf.Bytes(), &snapshots); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Objects[0].IsCollapsed || len(snapshots[0].Objects[0].CoordDist) != 9 {
+		t.Errorf("snapshot should hold the pre-collapse world, got %+v", snapshots)
+	}
+}
+
+func TestUndoRestoresRemovedObjects(t *testing.T) {
+	world := NewWorld(WithSize(3, 3), WithTopology(Toroidal), WithNeighborhood(Moore))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	c := NewQuantumObject("C", gridDist(3, 3))
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.AddQuantumObject(c)
+	world.Entangle(a, b, func(c [2]int) [2]int { return c })
+	world.EnableHistory(1)
+	world.CollapseAll()
+
+	if err := world.RemoveObject("B"); err != nil {
+		t.Fatal(err)
+	}
+	late := NewQuantumObject("D", gridDist(3, 3))
+	world.AddQuantumObject(late)
+	world.Entangle(a, late, func(c [2]int) [2]int { return c })
+	world.Boundary, world.Neighborhood = Bounded, VonNeumann
+
+	if err := world.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if len(world.Objects) != 3 || world.Objects[1] != b {
+		t.Fatalf("undo should restore B in place, got %v", world.Objects)
+	}
+	if world.Boundary != Toroidal || world.Neighborhood != Moore {
+		t.Errorf("undo should restore topology, got %v %v", world.Boundary, world.Neighborhood)
+	}
+	if got, ok := world.Find("B"); !ok || got != b {
+		t.Error("restored object should be findable by name")
+	}
+	if late.world != nil || len(late.entangled) != 0 || len(a.entangled) != 1 {
+		t.Error("object added after the snapshot should be detached")
+	}
+
+	b.Collapse()
+	if b.world != world || !a.IsCollapsed || a.FinalCoord != b.FinalCoord {
+		t.Errorf("restored B should be attached and entangled with A: %v %v", a, b)
+	}
+}
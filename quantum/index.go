@@ -0,0 +1,176 @@
+package quantum
+
+import "math"
+
+// SpatialIndex — сеточный индекс мира: плоскость разбита на корзины
+// BucketSize×BucketSize, и для каждой корзины известны объекты, имеющие
+// в ней ненулевой вес. Индекс ускоряет поиск по радиусу и MeasureInteractionWithin.
+type SpatialIndex struct {
+	BucketSize int
+
+	objects map[[2]int][]*QuantumObject            // корзина -> объекты с весом в ней
+	cells   map[*QuantumObject]map[[2]int][][2]int // объект -> корзина -> его клетки
+}
+
+// BuildSpatialIndex строит индекс по текущим распределениям объектов мира.
+// Сужение распределений (измерение, коллапс, Prune) оставляет индекс корректным;
+// методы мира и объекта, расширяющие или переносящие носитель (Step,
+// ApplyDecoherence, ResetAll, Shift, Reset, Diffuse, SetDistribution и т. п.),
+// сбрасывают индекс, и он перестраивается при следующем обращении.
+// После прямого изменения CoordDist индекс нужно перестроить вручную.
+func (w *World) BuildSpatialIndex(bucketSize int) *SpatialIndex {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buildIndexLocked(bucketSize)
+}
+
+func (w *World) buildIndexLocked(bucketSize int) *SpatialIndex {
+	bucketSize = max(bucketSize, 1)
+	idx := &SpatialIndex{
+		BucketSize: bucketSize,
+		objects:    make(map[[2]int][]*QuantumObject),
+		cells:      make(map[*QuantumObject]map[[2]int][][2]int),
+	}
+	for _, obj := range w.Objects {
+		obj.mu.RLock()
+		buckets := make(map[[2]int][][2]int)
+		for c, p := range obj.coordsLocked() {
+			if p > 0 {
+				b := idx.bucket(c)
+				buckets[b] = append(buckets[b], c)
+			}
+		}
+		obj.mu.RUnlock()
+		idx.cells[obj] = buckets
+		for b := range buckets {
+			idx.objects[b] = append(idx.objects[b], obj)
+		}
+	}
+	w.index = idx
+	return idx
+}
+
+// invalidateIndex сбрасывает индекс после операций, расширяющих носители.
+// Следующий запрос перестроит его с тем же размером корзины.
+func (w *World) invalidateIndex() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.invalidateIndexLocked()
+}
+
+// supportChanged сбрасывает индекс мира, в который добавлен объект, после
+// изменения его носителя. Вызывается без блокировки объекта, так как
+// блокировка мира берётся раньше блокировки объекта.
+func (q *QuantumObject) supportChanged() {
+	q.mu.RLock()
+	w := q.world
+	q.mu.RUnlock()
+	if w != nil {
+		w.invalidateIndex()
+	}
+}
+
+func (w *World) invalidateIndexLocked() {
+	if w.index != nil {
+		w.indexSize = w.index.BucketSize
+		w.index = nil
+	}
+}
+
+// spatialIndex возвращает актуальный индекс, перестраивая сброшенный.
+// Если индекс ни разу не строился, возвращает nil.
+func (w *World) spatialIndex() *SpatialIndex {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.index == nil && w.indexSize > 0 {
+		w.buildIndexLocked(w.indexSize)
+	}
+	return w.index
+}
+
+func (idx *SpatialIndex) bucket(c [2]int) [2]int {
+	return [2]int{floorDiv(c[0], idx.BucketSize), floorDiv(c[1], idx.BucketSize)}
+}
+
+// nearBuckets возвращает корзины, которые могут содержать клетки на расстоянии
+// не больше radius от c.
+func (idx *SpatialIndex) nearBuckets(c [2]int, radius float64) [][2]int {
+	b := idx.bucket(c)
+	r := int(math.Ceil(radius / float64(idx.BucketSize)))
+	out := make([][2]int, 0, (2*r+1)*(2*r+1))
+	for dx := -r; dx <= r; dx++ {
+		for dy := -r; dy <= r; dy++ {
+			out = append(out, [2]int{b[0] + dx, b[1] + dy})
+		}
+	}
+	return out
+}
+
+// ObjectsNear возвращает объекты, имеющие ненулевой вес на расстоянии не больше
+// radius от клетки c, в порядке их добавления в мир. Без построенного индекса
+// перебираются все объекты.
+func (w *World) ObjectsNear(c [2]int, radius float64) []*QuantumObject {
+	var candidate map[*QuantumObject]bool // nil — проверять все объекты
+	if idx := w.spatialIndex(); idx != nil && w.Boundary == Bounded {
+		candidate = make(map[*QuantumObject]bool)
+		for _, b := range idx.nearBuckets(c, radius) {
+			for _, obj := range idx.objects[b] {
+				candidate[obj] = true
+			}
+		}
+	}
+
+	var out []*QuantumObject
+	for _, obj := range w.objects() {
+		if candidate != nil && !candidate[obj] {
+			continue
+		}
+		obj.mu.RLock()
+		for cell, p := range obj.coordsLocked() {
+			if p > 0 && w.Distance(c, cell) <= radius {
+				out = append(out, obj)
+				break
+			}
+		}
+		obj.mu.RUnlock()
+	}
+	return out
+}
+
+// indexedPairJoint — вариант pairJoint, перебирающий для каждой клетки obj1
+// только клетки obj2 из соседних корзин индекса. Используется при radius
+// конечном, построенном индексе и режиме Bounded.
+func (w *World) indexedPairJoint(idx *SpatialIndex, obj2 *QuantumObject, radius float64,
+	kernel Kernel) jointFunc {
+	return func(d1, d2 map[[2]int]float64) (map[[2]int]float64, map[[2]int]float64) {
+		newDist1 := make(map[[2]int]float64)
+		newDist2 := make(map[[2]int]float64)
+		buckets := idx.cells[obj2]
+		for c1, p1 := range d1 {
+			if p1 <= 0 {
+				continue
+			}
+			for _, b := range idx.nearBuckets(c1, radius) {
+				for _, c2 := range buckets[b] {
+					p2 := d2[c2]
+					if p2 <= 0 {
+						continue
+					}
+					if weight := p1 * p2 * kernel(c1, c2); weight > 0 {
+						newDist1[c1] += weight
+						newDist2[c2] += weight
+					}
+				}
+			}
+		}
+		return newDist1, newDist2
+	}
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,101 @@
+package quantum
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestObjectsNear(t *testing.T) {
+	world := NewWorld(WithSize(100, 100))
+	a := NewQuantumObject("A", map[[2]int]float64{{10, 10}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{13, 14}: 1})
+	c := NewQuantumObject("C", map[[2]int]float64{{90, 90}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.AddQuantumObject(c)
+	world.BuildSpatialIndex(4)
+
+	near := world.ObjectsNear([2]int{10, 10}, 5)
+	if len(near) != 2 || near[0] != a || near[1] != b {
+		t.Errorf("expected A and B near (10,10), got %v", near)
+	}
+
+	world.AddQuantumObject(NewQuantumObject("D", map[[2]int]float64{{11, 10}: 1}))
+	if n := len(world.ObjectsNear([2]int{10, 10}, 5)); n != 3 {
+		t.Errorf("index should be rebuilt after adding an object, got %d", n)
+	}
+}
+
+func TestIndexedMeasureMatchesPairwise(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		world := NewWorld(WithSize(20, 20))
+		a := NewQuantumObject("A", map[[2]int]float64{{2, 2}: 1, {15, 15}: 1})
+		b := NewQuantumObject("B", map[[2]int]float64{{4, 3}: 1, {19, 0}: 1})
+		world.AddQuantumObject(a)
+		world.AddQuantumObject(b)
+		if indexed {
+			world.BuildSpatialIndex(3)
+		}
+		world.MeasureInteractionWithin(a, b, 3)
+		if a.FinalCoord != [2]int{2, 2} || b.FinalCoord != [2]int{4, 3} {
+			t.Errorf("indexed=%v: expected the only close pair, got %v %v", indexed, a, b)
+		}
+	}
+}
+
+func benchmarkMeasureWithin(b *testing.B, indexed bool) {
+	rng := rand.New(rand.NewSource(3))
+	centers := make([][2]int, 200)
+	for i := range centers {
+		centers[i] = [2]int{rng.Intn(100), rng.Intn(100)}
+	}
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		world := NewWorld(WithSize(100, 100))
+		for _, c := range centers {
+			dist := make(map[[2]int]float64)
+			for dx := -3; dx <= 3; dx++ {
+				for dy := -3; dy <= 3; dy++ {
+					if cell := [2]int{c[0] + dx, c[1] + dy}; world.InBounds(cell) {
+						dist[cell] = 1
+					}
+				}
+			}
+			world.AddQuantumObject(NewQuantumObject("O", dist))
+		}
+		if indexed {
+			world.BuildSpatialIndex(4)
+		}
+		b.StartTimer()
+		for j := 0; j+1 < len(world.Objects); j += 2 {
+			world.MeasureInteractionWithin(world.Objects[j], world.Objects[j+1], 2)
+		}
+	}
+}
+
+func BenchmarkMeasureInteractionWithin(b *testing.B)        { benchmarkMeasureWithin(b, false) }
+func BenchmarkMeasureInteractionWithinIndexed(b *testing.B) { benchmarkMeasureWithin(b, true) }
+
+func TestIndexInvalidatedByObjectMethods(t *testing.T) {
+	world := NewWorld(WithSize(20, 20))
+	a := NewQuantumObject("A", map[[2]int]float64{{2, 2}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{12, 12}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.BuildSpatialIndex(4)
+
+	b.Shift(-10, -10, Bounded)
+	if !world.MeasureInteractionWithin(a, b, 1) {
+		t.Fatal("shifting an object should invalidate the spatial index")
+	}
+
+	c := NewQuantumObject("C", map[[2]int]float64{{15, 15}: 1})
+	d := NewQuantumObject("D", map[[2]int]float64{{5, 5}: 1})
+	world.AddQuantumObject(c)
+	world.AddQuantumObject(d)
+	world.BuildSpatialIndex(4)
+	d.ResetWith(map[[2]int]float64{{15, 15}: 1})
+	if !world.MeasureInteractionWithin(c, d, 1) {
+		t.Error("ResetWith should invalidate the spatial index")
+	}
+}
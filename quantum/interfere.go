@@ -0,0 +1,136 @@
+package quantum
+
+// Interfere возвращает новый объект с распределением weight1·p1 + weight2·p2,
+// где p1 и p2 — нормированные распределения q и other. Разные знаки весов
+// моделируют деструктивную интерференцию: отрицательные значения обнуляются,
+// остаток нормируется. Исходные объекты не меняются; имя результата — "q+other".
+func (q *QuantumObject) Interfere(other *QuantumObject, weight1, weight2 float64) *QuantumObject {
+	p1, p2 := q.probabilities(), other.probabilities()
+	dist := make(map[[2]int]float64, len(p1)+len(p2))
+	for c, p := range p1 {
+		dist[c] += weight1 * p
+	}
+	for c, p := range p2 {
+		dist[c] += weight2 * p
+	}
+	for c, p := range dist {
+		if p <= 0 {
+			delete(dist, c)
+		}
+	}
+	obj := NewQuantumObject(q.Name+"+"+other.Name, dist)
+	obj.normalizeLocked()
+	return obj
+}
+
+// SuperposeObjects — Interfere с добавлением результата в мир.
+func (w *World) SuperposeObjects(obj1, obj2 *QuantumObject, w1, w2 float64) *QuantumObject {
+	obj := obj1.Interfere(obj2, w1, w2)
+	w.AddQuantumObject(obj)
+	return obj
+}
+
+// Add возвращает новый объект с распределением — суммой весов q и other
+// (без предварительной нормировки, объединение клеток), нормированной.
+// Имя результата совпадает с именем q.
+func (q *QuantumObject) Add(other *QuantumObject) *QuantumObject {
+	dist := q.weights()
+	for c, w := range other.weights() {
+		dist[c] += w
+	}
+	return q.derived(dist)
+}
+
+// Scale возвращает новый объект с весами q, умноженными на factor, после
+// нормировки. Нормировка снимает положительный множитель, поэтому результат
+// равен нормированной копии q; неположительный factor даёт пустое распределение.
+func (q *QuantumObject) Scale(factor float64) *QuantumObject {
+	dist := q.weights()
+	for c, w := range dist {
+		dist[c] = w * factor
+	}
+	return q.derived(dist)
+}
+
+// Multiply возвращает новый объект с поточечным произведением весов q и other,
+// нормированным, — байесовское обновление q по правдоподобию other.
+// Клетки вне общего носителя отбрасываются. Имя результата совпадает с именем q.
+func (q *QuantumObject) Multiply(other *QuantumObject) *QuantumObject {
+	dist, o := q.weights(), other.weights()
+	for c, w := range dist {
+		dist[c] = w * o[c]
+	}
+	return q.derived(dist)
+}
+
+// Conditional возвращает новый объект с распределением q при условии, что
+// объект находится в клетках, где pred истинен: вес остальных клеток
+// отбрасывается, остаток нормируется (байесовское обусловливание на область).
+// q не меняется; имя результата совпадает с именем q. Если ни одна клетка
+// с положительным весом не удовлетворяет pred, возвращается nil.
+func (q *QuantumObject) Conditional(pred func(x, y int) bool) *QuantumObject {
+	dist := q.weights()
+	for c, w := range dist {
+		if w <= 0 || !pred(c[0], c[1]) {
+			delete(dist, c)
+		}
+	}
+	if len(dist) == 0 {
+		return nil
+	}
+	return q.derived(dist)
+}
+
+// Apply заменяет каждый вес w клетки (x, y) на f(x, y, w), отбрасывая клетки,
+// где f вернула неположительное значение; при renormalize результат
+// нормируется. Так поле (например, гауссов множитель вокруг точки) применяется
+// к распределению одной строкой; несколько полей компонуются повторными вызовами.
+// Возвращает, изменилось ли распределение: коллапсированный объект не
+// меняется, и если f отбросила все клетки, распределение остаётся прежним.
+func (q *QuantumObject) Apply(f func(x, y int, w float64) float64, renormalize bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.IsCollapsed {
+		return false
+	}
+	q.sparseLocked()
+	dist := make(map[[2]int]float64, len(q.CoordDist))
+	for c, w := range q.CoordDist {
+		if v := f(c[0], c[1], w); v > 0 {
+			dist[c] = v
+		}
+	}
+	if len(dist) == 0 {
+		return false
+	}
+	q.CoordDist = dist
+	if renormalize {
+		q.normalizeLocked()
+	}
+	return true
+}
+
+// weights возвращает копию ненормированного распределения.
+func (q *QuantumObject) weights() map[[2]int]float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	coords := q.coordsLocked()
+	dist := make(map[[2]int]float64, len(coords))
+	for c, w := range coords {
+		dist[c] = w
+	}
+	return dist
+}
+
+// derived создаёт несколлапсированный объект с именем q из dist:
+// неположительные веса отбрасываются, остаток нормируется.
+func (q *QuantumObject) derived(dist map[[2]int]float64) *QuantumObject {
+	for c, w := range dist {
+		if w <= 0 {
+			delete(dist, c)
+		}
+	}
+	obj := NewQuantumObject(q.Name, dist)
+	obj.normalizeLocked()
+	return obj
+}
@@ -0,0 +1,123 @@
+package quantum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterfere(t *testing.T) {
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 0}: 1, {2, 0}: 1})
+
+	sum := a.Interfere(b, 1, 1)
+	if math.Abs(sum.CoordDist[[2]int{1, 0}]-0.5) > 1e-12 || math.Abs(sum.CoordDist[[2]int{0, 0}]-0.25) > 1e-12 {
+		t.Errorf("constructive interference should reinforce the overlap, got %v", sum.CoordDist)
+	}
+
+	diff := a.Interfere(b, 1, -1)
+	if len(diff.CoordDist) != 1 || diff.CoordDist[[2]int{0, 0}] != 1 {
+		t.Errorf("destructive interference should cancel the overlap, got %v", diff.CoordDist)
+	}
+	if a.CoordDist[[2]int{0, 0}] != 1 {
+		t.Error("Interfere must not modify its inputs")
+	}
+}
+
+func TestSuperposeObjects(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{2, 2}: 1})
+	obj := world.SuperposeObjects(a, b, 1, 3)
+	if len(world.Objects) != 1 || world.Objects[0] != obj || obj.Name != "A+B" {
+		t.Fatalf("result should be registered in the world, got %v", world.Objects)
+	}
+	if p := obj.ProbabilityAt(2, 2); math.Abs(p-0.75) > 1e-12 {
+		t.Errorf("weights should set relative mass, got %f", p)
+	}
+}
+
+func TestDistributionArithmetic(t *testing.T) {
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 3})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1, {2, 2}: 3})
+
+	sum := a.Add(b)
+	if sum.Name != "A" || len(sum.CoordDist) != 3 {
+		t.Fatalf("sum should keep the receiver name and union the cells: %v", sum)
+	}
+	if p := sum.ProbabilityAt(1, 1); math.Abs(p-0.5) > 1e-12 {
+		t.Errorf("shared cell should sum raw weights, got %f", p)
+	}
+
+	scaled := a.Scale(10)
+	if p := scaled.CoordDist[[2]int{1, 1}]; math.Abs(p-0.75) > 1e-12 {
+		t.Errorf("scaled distribution should be normalized, got %f", p)
+	}
+	if len(a.Scale(-1).CoordDist) != 0 {
+		t.Error("non-positive factor should give an empty distribution")
+	}
+
+	post := a.Multiply(b)
+	if len(post.CoordDist) != 1 || post.CoordDist[[2]int{1, 1}] != 1 {
+		t.Errorf("product should keep only the common support: %v", post.CoordDist)
+	}
+	if a.CoordDist[[2]int{0, 0}] != 1 || b.CoordDist[[2]int{2, 2}] != 3 {
+		t.Error("operands should not be modified")
+	}
+}
+
+func TestApplyGaussianField(t *testing.T) {
+	world := NewWorld(WithSize(7, 7))
+	tree := world.GaussFactor
+	obj := NewQuantumObject("seed", UniformDistribution(7, 7))
+	if !obj.Apply(func(x, y int, w float64) float64 {
+		return w * tree([2]int{x, y}, [2]int{3, 3}, 1.5)
+	}, true) {
+		t.Fatal("Apply should report a change")
+	}
+
+	want := GaussianDistribution(7, 7, 3, 3, 1.5)
+	for c, p := range want {
+		if math.Abs(obj.CoordDist[c]-p) > 1e-12 {
+			t.Errorf("cell %v = %v, want %v", c, obj.CoordDist[c], p)
+		}
+	}
+
+	before := SparseDistribution(obj.CoordDist).Total()
+	obj.Apply(func(x, y int, w float64) float64 {
+		if x < 3 {
+			return 0
+		}
+		return w
+	}, false)
+	if obj.ProbabilityAt(2, 3) != 0 || len(obj.CoordDist) != 28 {
+		t.Errorf("cells where f returns 0 should be dropped, %d left", len(obj.CoordDist))
+	}
+	if total := SparseDistribution(obj.CoordDist).Total(); total >= before || total >= 1 {
+		t.Errorf("without renormalize the weights should keep their scale, total %v", total)
+	}
+	if obj.Apply(func(int, int, float64) float64 { return -1 }, true) || len(obj.CoordDist) != 28 {
+		t.Error("dropping every cell should report false and leave the distribution unchanged")
+	}
+	obj.Collapse()
+	if obj.Apply(func(_, _ int, w float64) float64 { return 2 * w }, true) {
+		t.Error("collapsed object should not change")
+	}
+}
+
+func TestConditional(t *testing.T) {
+	tree := NewQuantumObject("tree", map[[2]int]float64{{0, 0}: 1, {1, 2}: 3, {3, 0}: 4})
+	leftHalf := func(x, _ int) bool { return x < 2 }
+	given := tree.Conditional(leftHalf)
+	if given == nil || given.Name != "tree" {
+		t.Fatalf("Conditional = %v", given)
+	}
+	if got := given.ProbabilityAt(1, 2); math.Abs(got-0.75) > 1e-12 || len(given.CoordDist) != 2 {
+		t.Errorf("conditioned distribution = %v, want {(0,0): 0.25, (1,2): 0.75}", given.CoordDist)
+	}
+	if len(tree.CoordDist) != 3 || tree.CoordDist[[2]int{3, 0}] != 4 {
+		t.Errorf("original should be unchanged, got %v", tree.CoordDist)
+	}
+	if got := tree.Conditional(func(x, _ int) bool { return x > 5 }); got != nil {
+		t.Errorf("impossible condition should give nil, got %v", got)
+	}
+}
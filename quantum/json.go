@@ -0,0 +1,170 @@
+package quantum
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// objectJSON — JSON-представление QuantumObject. encoding/json не умеет
+// использовать [2]int как ключ map, поэтому распределение хранится массивом
+// записей {x, y, weight}. Для совместимости при чтении принимается и прежняя
+// форма — объект с ключами "x,y".
+type objectJSON struct {
+	Name        string            `json:"name"`
+	CoordDist   json.RawMessage   `json:"coordDist"`
+	IsCollapsed bool              `json:"isCollapsed"`
+	FinalCoord  [2]int            `json:"finalCoord"`
+	Prior       json.RawMessage   `json:"prior,omitempty"` // снимок до коллапса для Reset
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// cellJSON — одна запись распределения.
+type cellJSON struct {
+	X      int     `json:"x"`
+	Y      int     `json:"y"`
+	Weight float64 `json:"weight"`
+}
+
+// worldJSON — JSON-представление World. Поля с нулевыми значениями по умолчанию
+// опускаются, так что прежние документы читаются без изменений.
+type worldJSON struct {
+	Width          int              `json:"width"`
+	Height         int              `json:"height"`
+	Boundary       BoundaryMode     `json:"boundary,omitempty"`
+	Neighborhood   Neighborhood     `json:"neighborhood,omitempty"`
+	MinProbability float64          `json:"minProbability,omitempty"`
+	Objects        []*QuantumObject `json:"objects"`
+}
+
+// decodeDist разбирает распределение в форме массива записей или объекта "x,y".
+func decodeDist(data json.RawMessage) (map[[2]int]float64, error) {
+	dist := make(map[[2]int]float64)
+	if len(data) == 0 || string(data) == "null" {
+		return dist, nil
+	}
+	if data[0] == '{' {
+		var legacy map[string]float64
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		for k, w := range legacy {
+			c, err := parseCoordKey(k)
+			if err != nil {
+				return nil, err
+			}
+			dist[c] = w
+		}
+		return dist, nil
+	}
+	var cells []cellJSON
+	if err := json.Unmarshal(data, &cells); err != nil {
+		return nil, err
+	}
+	for _, cell := range cells {
+		dist[[2]int{cell.X, cell.Y}] = cell.Weight
+	}
+	return dist, nil
+}
+
+func parseCoordKey(s string) ([2]int, error) {
+	var c [2]int
+	if _, err := fmt.Sscanf(s, "%d,%d", &c[0], &c[1]); err != nil {
+		return c, fmt.Errorf("invalid coordinate %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// MarshalJSON кодирует объект вместе с распределением, флагом коллапса
+// и финальной координатой. Генератор и запутывающие связи не сохраняются.
+// Записи упорядочены по (x, y), поэтому вывод детерминирован.
+func (q *QuantumObject) MarshalJSON() ([]byte, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	dist, err := encodeDist(q.coordsLocked())
+	if err != nil {
+		return nil, err
+	}
+	raw := objectJSON{
+		Name:        q.Name,
+		CoordDist:   dist,
+		IsCollapsed: q.IsCollapsed,
+		FinalCoord:  q.FinalCoord,
+		Metadata:    q.Metadata,
+	}
+	if q.prior != nil {
+		if raw.Prior, err = encodeDist(q.prior); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// encodeDist кодирует распределение массивом записей, упорядоченных по (x, y).
+func encodeDist(dist map[[2]int]float64) (json.RawMessage, error) {
+	cells := make([]cellJSON, 0, len(dist))
+	for _, c := range sortedCoords(dist) {
+		cells = append(cells, cellJSON{X: c[0], Y: c[1], Weight: dist[c]})
+	}
+	return json.Marshal(cells)
+}
+
+// UnmarshalJSON восстанавливает объект, закодированный MarshalJSON.
+func (q *QuantumObject) UnmarshalJSON(data []byte) error {
+	var raw objectJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	dist, err := decodeDist(raw.CoordDist)
+	if err != nil {
+		return err
+	}
+	var prior map[[2]int]float64
+	if len(raw.Prior) > 0 {
+		if prior, err = decodeDist(raw.Prior); err != nil {
+			return err
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.prior = prior
+	q.Name = raw.Name
+	q.dist, q.CoordDist = nil, dist
+	q.IsCollapsed = raw.IsCollapsed
+	q.FinalCoord = raw.FinalCoord
+	q.Metadata = raw.Metadata
+	return nil
+}
+
+// MarshalJSON кодирует размеры, топологию, порог вероятности мира и все его объекты.
+func (w *World) MarshalJSON() ([]byte, error) {
+	return json.Marshal(worldJSON{
+		Width:          w.Width,
+		Height:         w.Height,
+		Boundary:       w.Boundary,
+		Neighborhood:   w.Neighborhood,
+		MinProbability: w.minProb,
+		Objects:        w.objects(),
+	})
+}
+
+// UnmarshalJSON восстанавливает мир, закодированный MarshalJSON.
+func (w *World) UnmarshalJSON(data []byte) error {
+	var raw worldJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	w.Width = raw.Width
+	w.Height = raw.Height
+	w.Boundary = raw.Boundary
+	w.Neighborhood = raw.Neighborhood
+	w.SetMinProbability(raw.MinProbability)
+	w.mu.Lock()
+	w.Objects = nil
+	w.names = nil
+	w.invalidateIndexLocked()
+	w.mu.Unlock()
+	for _, obj := range raw.Objects {
+		w.AddQuantumObject(obj)
+	}
+	return nil
+}
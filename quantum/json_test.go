@@ -0,0 +1,101 @@
+package quantum
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestWorldJSONRoundTrip(t *testing.T) {
+	world := NewWorld(WithSize(5, 5), WithTopology(Toroidal), WithNeighborhood(Moore),
+		WithMinProbability(0.01))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 0.25, {3, 4}: 0.75})
+	b := NewQuantumObject("B", map[[2]int]float64{{2, 2}: 1})
+	b.Collapse()
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+
+	data, err := json.Marshal(world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored World
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Width != 5 || restored.Height != 5 || len(restored.Objects) != 2 {
+		t.Fatalf("world shape mismatch: %+v", &restored)
+	}
+	if restored.Boundary != Toroidal || restored.Neighborhood != Moore || restored.MinProbability() != 0.01 {
+		t.Errorf("topology not restored: %v %v %v",
+			restored.Boundary, restored.Neighborhood, restored.MinProbability())
+	}
+	ra, rb := restored.Objects[0], restored.Objects[1]
+	if ra.Name != "A" || ra.IsCollapsed || ra.CoordDist[[2]int{3, 4}] != 0.75 || len(ra.CoordDist) != 2 {
+		t.Errorf("uncollapsed object not restored: %+v", ra)
+	}
+	if !rb.IsCollapsed || rb.FinalCoord != [2]int{2, 2} {
+		t.Errorf("collapsed object not restored: %+v", rb)
+	}
+}
+
+func TestObjectJSONDeepEqual(t *testing.T) {
+	world := NewWorld(WithSize(4, 4))
+	world.AddQuantumObject(NewQuantumObject("A", map[[2]int]float64{{0, 1}: 0.1, {2, 3}: 0.2, {3, 3}: 0.7}))
+	collapsed := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1})
+	collapsed.Collapse()
+	world.AddQuantumObject(collapsed)
+
+	data, err := json.Marshal(world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(World)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(world, restored) {
+		t.Errorf("round trip mismatch:\n%s", data)
+	}
+}
+
+func TestObjectJSONLegacyKeys(t *testing.T) {
+	var obj QuantumObject
+	data := []byte(`{"name":"A","coordDist":{"1,2":0.5,"3,4":0.5},"isCollapsed":false,"finalCoord":[0,0]}`)
+	if err := json.Unmarshal(data, &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj.CoordDist[[2]int{1, 2}] != 0.5 || obj.CoordDist[[2]int{3, 4}] != 0.5 {
+		t.Errorf("legacy coordinate keys not decoded: %v", obj.CoordDist)
+	}
+}
+
+func TestObjectJSONMetadata(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1}, WithMetadata("mass", "3"))
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored QuantumObject
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Metadata["mass"] != "3" {
+		t.Errorf("metadata should survive JSON, got %v", restored.Metadata)
+	}
+}
+
+func TestWorldJSONReflecting(t *testing.T) {
+	data, err := json.Marshal(NewWorld(WithSize(3, 2), WithTopology(Reflecting)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(World)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Boundary != Reflecting || restored.Neighborhood != VonNeumann || restored.MinProbability() != 0 {
+		t.Errorf("reflecting world not restored: %s", data)
+	}
+}
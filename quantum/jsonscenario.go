@@ -0,0 +1,352 @@
+package quantum
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ScenarioSchema — JSON Schema сценария для LoadWorldFromJSON. Правила,
+// на которые ссылаются SchemaError, указываются JSON-указателями в этой схеме;
+// её же можно передать внешним инструментам для проверки файлов.
+//
+//go:embed scenario.schema.json
+var ScenarioSchema string
+
+// scenarioSchemaID — имя схемы в сообщениях об ошибках.
+const scenarioSchemaID = "scenario.schema.json"
+
+// SchemaErrorKind — вид нарушения в сценарии JSON.
+type SchemaErrorKind int
+
+const (
+	MalformedJSON       SchemaErrorKind = iota // документ не разбирается как JSON
+	MissingField                               // нет обязательного поля
+	WrongType                                  // значение не того типа
+	UnknownDistribution                        // неизвестный тип объекта
+	InvalidDimensions                          // размеры мира меньше 1
+	InvalidParameter                           // параметр вне допустимого диапазона
+)
+
+func (k SchemaErrorKind) String() string {
+	switch k {
+	case MalformedJSON:
+		return "malformed JSON"
+	case MissingField:
+		return "missing field"
+	case WrongType:
+		return "wrong type"
+	case UnknownDistribution:
+		return "unknown distribution"
+	case InvalidDimensions:
+		return "invalid dimensions"
+	case InvalidParameter:
+		return "invalid parameter"
+	}
+	return fmt.Sprintf("SchemaErrorKind(%d)", int(k))
+}
+
+// SchemaError — нарушение схемы: место в документе (JSON-указатель,
+// пустой для корня), вид, нарушенное правило схемы и описание.
+type SchemaError struct {
+	Path    string
+	Kind    SchemaErrorKind
+	Rule    string
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	if e.Rule == "" {
+		return fmt.Sprintf("scenario %s: %s", path, e.Message)
+	}
+	return fmt.Sprintf("scenario %s: %s (%s#%s)", path, e.Message, scenarioSchemaID, e.Rule)
+}
+
+// LoadWorldFromJSON читает сценарий в формате JSON с той же структурой,
+// что и LoadWorldFromYAML, и строит мир. Документ сначала проверяется
+// ValidateScenarioJSON; при нарушениях возвращается объединение всех
+// SchemaError, которые можно извлечь через errors.As.
+func LoadWorldFromJSON(r io.Reader) (*World, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadScenario(data)
+}
+
+// loadScenario проверяет документ JSON по ScenarioSchema и строит мир.
+// Через него проходят и JSON, и YAML после преобразования в JSON.
+func loadScenario(data []byte) (*World, error) {
+	if report := ValidateScenarioJSON(data); len(report) > 0 {
+		errs := make([]error, len(report))
+		for i, e := range report {
+			errs[i] = e
+		}
+		return nil, errors.Join(errs...)
+	}
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return buildScenario(root)
+}
+
+// ValidateScenarioJSON проверяет документ по ScenarioSchema, не строя мир,
+// и возвращает все нарушения в порядке обхода документа. Пустой результат
+// означает, что LoadWorldFromJSON примет документ; отсутствие объектов,
+// названных во взаимодействиях, выявляется только при загрузке.
+func ValidateScenarioJSON(data []byte) []SchemaError {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []SchemaError{{Kind: MalformedJSON, Message: err.Error()}}
+	}
+	v := &scenarioValidator{}
+	root, ok := v.object(doc, "", "/type")
+	if !ok {
+		return v.errs
+	}
+	if world, ok := v.objectField(root, "", "", "world", true); ok {
+		const rule = "/properties/world"
+		for _, key := range []string{"width", "height"} {
+			if n, ok := v.number(world, "/world", rule, key, true, true); ok && n < 1 {
+				v.add("/world/"+key, InvalidDimensions, rule+"/properties/"+key+"/minimum",
+					"%s must be at least 1, got %v", key, n)
+			}
+		}
+		v.enum(world, "/world", rule, "boundary", InvalidParameter, "bounded", "toroidal", "reflecting")
+	}
+	for i, item := range v.array(root, "", "", "objects") {
+		v.scenarioObject(item, "/objects/"+strconv.Itoa(i))
+	}
+	const interactionRule = "/properties/interactions/items"
+	for i, item := range v.array(root, "", "", "interactions") {
+		path := "/interactions/" + strconv.Itoa(i)
+		step, ok := v.object(item, path, interactionRule+"/type")
+		if !ok {
+			continue
+		}
+		names, ok := v.field(step, path, interactionRule, "measure", true)
+		if !ok {
+			continue
+		}
+		list, isList := names.([]any)
+		if !isList || len(list) != 2 {
+			v.add(path+"/measure", WrongType, interactionRule+"/properties/measure",
+				"measure must list exactly two object names")
+			continue
+		}
+		for j, name := range list {
+			if _, isString := name.(string); !isString {
+				v.add(fmt.Sprintf("%s/measure/%d", path, j), WrongType,
+					interactionRule+"/properties/measure/items/type", "object name must be a string")
+			}
+		}
+	}
+	return v.errs
+}
+
+// scenarioObjectRules — правила схемы для параметров каждого типа объекта:
+// индекс ветви allOf и параметры с ограничениями.
+var scenarioObjectRules = map[string]struct {
+	branch int
+	params []scenarioParam
+}{
+	"gaussian": {0, []scenarioParam{{key: "cx", integer: true}, {key: "cy", integer: true}, {key: "sigma", min: 0, exclusive: true, bounded: true}}},
+	"uniform":  {-1, nil},
+	"point":    {1, []scenarioParam{{key: "x", integer: true}, {key: "y", integer: true}}},
+	"ring":     {2, []scenarioParam{{key: "cx", integer: true}, {key: "cy", integer: true}, {key: "radius", integer: true, min: 0, bounded: true}, {key: "width", integer: true, min: 1, bounded: true}}},
+	"cells":    {3, nil},
+}
+
+// scenarioParam — числовой параметр объекта: целый ли он и нижняя граница.
+type scenarioParam struct {
+	key       string
+	integer   bool
+	bounded   bool
+	min       float64
+	exclusive bool
+}
+
+func (v *scenarioValidator) scenarioObject(item any, path string) {
+	const rule = "/$defs/object"
+	obj, ok := v.object(item, path, rule+"/type")
+	if !ok {
+		return
+	}
+	if name, ok := v.field(obj, path, rule, "name", true); ok {
+		if s, isString := name.(string); !isString || s == "" {
+			v.add(path+"/name", WrongType, rule+"/properties/name", "name must be a non-empty string")
+		}
+	}
+	collapsed := false
+	if c, ok := obj["collapsed"]; ok {
+		if collapsed, ok = c.(bool); !ok {
+			v.add(path+"/collapsed", WrongType, rule+"/properties/collapsed/type", "collapsed must be a boolean")
+		}
+	}
+	if collapsed {
+		v.field(obj, path, rule+"/allOf/4/then", "at", true)
+	}
+	if at, ok := obj["at"]; ok {
+		c, isList := at.([]any)
+		if !isList || len(c) != 2 {
+			v.add(path+"/at", WrongType, rule+"/properties/at", "at must be [x, y]")
+		}
+		for j, val := range c[:min(len(c), 2)] {
+			if n, isNum := val.(float64); !isNum || n != math.Trunc(n) {
+				v.add(fmt.Sprintf("%s/at/%d", path, j), WrongType,
+					fmt.Sprintf("%s/properties/at/prefixItems/%d/type", rule, j), "at coordinates must be integers")
+			}
+		}
+	}
+	typ, ok := v.field(obj, path, rule, "type", true)
+	if !ok {
+		return
+	}
+	name, _ := typ.(string)
+	rules, known := scenarioObjectRules[name]
+	if !known {
+		kinds := make([]string, 0, len(scenarioObjectRules))
+		for k := range scenarioObjectRules {
+			kinds = append(kinds, k)
+		}
+		slices.Sort(kinds)
+		v.add(path+"/type", UnknownDistribution, rule+"/properties/type/enum",
+			"unknown distribution type %v (want one of %v)", typ, kinds)
+		return
+	}
+	branch := fmt.Sprintf("%s/allOf/%d/then", rule, rules.branch)
+	for _, p := range rules.params {
+		n, ok := v.number(obj, path, branch, p.key, true, p.integer)
+		if !ok || !p.bounded {
+			continue
+		}
+		switch {
+		case p.exclusive && n <= p.min:
+			v.add(path+"/"+p.key, InvalidParameter, branch+"/properties/"+p.key+"/exclusiveMinimum",
+				"%s must be greater than %v, got %v", p.key, p.min, n)
+		case !p.exclusive && n < p.min:
+			v.add(path+"/"+p.key, InvalidParameter, branch+"/properties/"+p.key+"/minimum",
+				"%s must be at least %v, got %v", p.key, p.min, n)
+		}
+	}
+	if name == "cells" {
+		v.cells(obj, path, branch)
+	}
+}
+
+func (v *scenarioValidator) cells(obj map[string]any, path, branch string) {
+	raw, ok := v.field(obj, path, branch, "cells", true)
+	if !ok {
+		return
+	}
+	rule := branch + "/properties/cells"
+	cells, isList := raw.([]any)
+	if !isList || len(cells) == 0 {
+		v.add(path+"/cells", WrongType, rule, "cells must be a non-empty array")
+		return
+	}
+	for i, cell := range cells {
+		cellPath := fmt.Sprintf("%s/cells/%d", path, i)
+		c, isList := cell.([]any)
+		if !isList || len(c) != 3 {
+			v.add(cellPath, WrongType, rule+"/items", "cell must be [x, y, weight]")
+			continue
+		}
+		for j, val := range c {
+			n, isNum := val.(float64)
+			switch {
+			case !isNum || (j < 2 && n != math.Trunc(n)):
+				v.add(fmt.Sprintf("%s/%d", cellPath, j), WrongType,
+					fmt.Sprintf("%s/items/prefixItems/%d/type", rule, j), "cell component must be a number, x and y integers")
+			case j == 2 && n < 0:
+				v.add(fmt.Sprintf("%s/%d", cellPath, j), InvalidParameter,
+					rule+"/items/prefixItems/2/minimum", "cell weight must be non-negative, got %v", n)
+			}
+		}
+	}
+}
+
+// scenarioValidator накапливает нарушения при обходе документа.
+type scenarioValidator struct {
+	errs []SchemaError
+}
+
+func (v *scenarioValidator) add(path string, kind SchemaErrorKind, rule, format string, args ...any) {
+	v.errs = append(v.errs, SchemaError{Path: path, Kind: kind, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *scenarioValidator) object(val any, path, rule string) (map[string]any, bool) {
+	m, ok := val.(map[string]any)
+	if !ok {
+		v.add(path, WrongType, rule, "expected an object")
+	}
+	return m, ok
+}
+
+// field возвращает m[key]; отсутствие обязательного поля нарушает правило
+// rule/required родителя.
+func (v *scenarioValidator) field(m map[string]any, path, rule, key string, required bool) (any, bool) {
+	val, ok := m[key]
+	if !ok && required {
+		v.add(path, MissingField, rule+"/required", "missing required field %q", key)
+	}
+	return val, ok
+}
+
+func (v *scenarioValidator) objectField(m map[string]any, path, rule, key string, required bool) (map[string]any, bool) {
+	val, ok := v.field(m, path, rule, key, required)
+	if !ok {
+		return nil, false
+	}
+	return v.object(val, path+"/"+key, rule+"/properties/"+key+"/type")
+}
+
+// array возвращает элементы необязательного поля-массива.
+func (v *scenarioValidator) array(m map[string]any, path, rule, key string) []any {
+	val, ok := m[key]
+	if !ok {
+		return nil
+	}
+	list, ok := val.([]any)
+	if !ok {
+		v.add(path+"/"+key, WrongType, rule+"/properties/"+key+"/type", "expected an array")
+	}
+	return list
+}
+
+func (v *scenarioValidator) number(m map[string]any, path, rule, key string, required, integer bool) (float64, bool) {
+	val, ok := v.field(m, path, rule, key, required)
+	if !ok {
+		return 0, false
+	}
+	n, isNum := val.(float64)
+	switch {
+	case !isNum:
+		v.add(path+"/"+key, WrongType, rule+"/properties/"+key+"/type", "%s must be a number", key)
+		return 0, false
+	case integer && n != math.Trunc(n):
+		v.add(path+"/"+key, WrongType, rule+"/properties/"+key+"/type", "%s must be an integer, got %v", key, n)
+		return 0, false
+	}
+	return n, true
+}
+
+func (v *scenarioValidator) enum(m map[string]any, path, rule, key string, kind SchemaErrorKind, allowed ...string) {
+	val, ok := m[key]
+	if !ok {
+		return
+	}
+	if s, isString := val.(string); !isString || !slices.Contains(allowed, s) {
+		v.add(path+"/"+key, kind, rule+"/properties/"+key+"/enum", "%s must be one of %v, got %v", key, allowed, val)
+	}
+}
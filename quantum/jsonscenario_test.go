@@ -0,0 +1,287 @@
+package quantum
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const sampleScenarioJSON = `{
+  "world": {"width": 8, "height": 6, "boundary": "toroidal"},
+  "objects": [
+    {"name": "john", "type": "gaussian", "cx": 3, "cy": 3, "sigma": 1.5},
+    {"name": "tree", "type": "point", "x": 3, "y": 3},
+    {"name": "fog", "type": "cells", "cells": [[0, 0, 1], [1, 0, 3]], "collapsed": false}
+  ],
+  "interactions": [{"measure": ["john", "tree"]}]
+}`
+
+func TestLoadWorldFromJSON(t *testing.T) {
+	world, err := LoadWorldFromJSON(strings.NewReader(sampleScenarioJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world.Width != 8 || world.Boundary != Toroidal || len(world.Objects) != 3 {
+		t.Fatalf("unexpected world %dx%d %s with %d objects", world.Width, world.Height, world.Boundary, len(world.Objects))
+	}
+	john, _ := world.Find("john")
+	if !john.IsCollapsed || john.FinalCoord != [2]int{3, 3} {
+		t.Errorf("measure step should collapse john onto the tree: %v", john)
+	}
+	if fog, _ := world.Find("fog"); fog.ProbabilityAt(1, 0) != 0.75 || fog.IsCollapsed {
+		t.Errorf("explicit cells should load: %v", fog)
+	}
+}
+
+// invalidScenarios — документы с ровно одним нарушением; общие для проверки
+// ValidateScenarioJSON и сверки со схемой.
+var invalidScenarios = []struct {
+	doc  string
+	kind SchemaErrorKind
+	path string
+}{
+	{`{"world": {"width": 3`, MalformedJSON, ""},
+	{`{"objects": []}`, MissingField, ""},
+	{`{"world": {"width": -2, "height": 3}}`, InvalidDimensions, "/world/width"},
+	{`{"world": {"width": 3, "height": 3}, "objects": [{"name": "a", "type": "blob"}]}`, UnknownDistribution, "/objects/0/type"},
+	{`{"world": {"width": 3, "height": 3}, "objects": [{"name": "a", "type": "gaussian", "cx": 1, "cy": 1, "sigma": 0}]}`, InvalidParameter, "/objects/0/sigma"},
+	{`{"world": {"width": 3, "height": 3}, "objects": [{"name": "a", "type": "point", "x": 1.5, "y": 1}]}`, WrongType, "/objects/0/x"},
+	{`{"world": {"width": 3, "height": 3}, "objects": [{"name": "a", "type": "cells", "cells": [[0, 0, -1]]}]}`, InvalidParameter, "/objects/0/cells/0/2"},
+	{`{"world": {"width": 3, "height": 3}, "interactions": [{"measure": ["a"]}]}`, WrongType, "/interactions/0/measure"},
+	{`{"world": {"width": 3, "height": 3}, "objects": [{"name": "a", "type": "uniform", "collapsed": true}]}`, MissingField, "/objects/0"},
+	{`{"world": {"width": 3, "height": 3}, "objects": [{"name": "a", "type": "uniform", "collapsed": true, "at": [1]}]}`, WrongType, "/objects/0/at"},
+}
+
+func TestValidateScenarioJSON(t *testing.T) {
+	if errs := ValidateScenarioJSON([]byte(sampleScenarioJSON)); len(errs) != 0 {
+		t.Fatalf("valid scenario reported %v", errs)
+	}
+	var schema any
+	if err := json.Unmarshal([]byte(ScenarioSchema), &schema); err != nil {
+		t.Fatalf("embedded schema is not valid JSON: %v", err)
+	}
+	for _, tt := range invalidScenarios {
+		errs := ValidateScenarioJSON([]byte(tt.doc))
+		if len(errs) != 1 || errs[0].Kind != tt.kind || errs[0].Path != tt.path {
+			t.Errorf("%s: got %v, want one %s at %q", tt.doc, errs, tt.kind, tt.path)
+			continue
+		}
+		if rule := errs[0].Rule; rule != "" && !schemaHas(schema, rule) {
+			t.Errorf("%s: rule %q does not point into the schema", tt.doc, rule)
+		}
+	}
+}
+
+// schemaHas сообщает, разрешается ли JSON-указатель pointer в документе schema.
+func schemaHas(schema any, pointer string) bool {
+	for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		switch node := schema.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return false
+			}
+			schema = next
+		case []any:
+			i := 0
+			for _, ch := range part {
+				i = i*10 + int(ch-'0')
+			}
+			if i >= len(node) {
+				return false
+			}
+			schema = node[i]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func TestLoadWorldFromJSONErrors(t *testing.T) {
+	_, err := LoadWorldFromJSON(strings.NewReader(`{"world": {"width": 0, "height": -1}}`))
+	var se SchemaError
+	if !errors.As(err, &se) || se.Kind != InvalidDimensions {
+		t.Fatalf("expected dimension error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "/world/height") || !strings.Contains(err.Error(), scenarioSchemaID) {
+		t.Errorf("every violation should be reported with its schema rule: %v", err)
+	}
+
+	_, err = LoadWorldFromJSON(strings.NewReader(`{"world": {"width": 3, "height": 3}, "interactions": [{"measure": ["a", "b"]}]}`))
+	var nf *ObjectNotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("unknown interaction object should be reported, got %v", err)
+	}
+}
+
+// TestScenarioSchemaAgreement прогоняет общие документы через встроенную
+// ScenarioSchema и ValidateScenarioJSON: оба должны одинаково принимать
+// и отвергать документ, а каждое нарушение валидатора — указывать на место,
+// которое отвергает и схема.
+func TestScenarioSchemaAgreement(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(ScenarioSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	docs := []string{
+		sampleScenarioJSON,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "uniform", "collapsed": true, "at": [1, 0]}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "ring", "cx": 0, "cy": 0, "radius": 0, "width": 1}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "uniform", "collapsed": false}]}`,
+		`[]`,
+		`{"world": "3x3"}`,
+		`{"world": {"width": 2.5, "height": 2}}`,
+		`{"world": {"width": 2, "height": 2, "boundary": "spherical"}}`,
+		`{"world": {"width": 2, "height": 2}, "objects": {}}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [7]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "", "type": "uniform"}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"type": "uniform"}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a"}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "ring", "cx": 0, "cy": 0, "radius": -1, "width": 0}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "gaussian", "cx": "0", "cy": 0, "sigma": 1}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "cells", "cells": []}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "cells", "cells": [[0, 0]]}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "cells", "cells": [[0, 0, "1"]]}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "uniform", "collapsed": "yes"}]}`,
+		`{"world": {"width": 2, "height": 2}, "objects": [{"name": "a", "type": "uniform", "at": [0.5, 0]}]}`,
+		`{"world": {"width": 2, "height": 2}, "interactions": {}}`,
+		`{"world": {"width": 2, "height": 2}, "interactions": [{}]}`,
+		`{"world": {"width": 2, "height": 2}, "interactions": [{"measure": ["a", 1]}]}`,
+	}
+	for _, tt := range invalidScenarios {
+		if tt.kind != MalformedJSON {
+			docs = append(docs, tt.doc)
+		}
+	}
+	for _, doc := range docs {
+		var v any
+		if err := json.Unmarshal([]byte(doc), &v); err != nil {
+			t.Fatalf("%s: %v", doc, err)
+		}
+		c := &schemaChecker{root: schema}
+		c.check(schema, v, "")
+		errs := ValidateScenarioJSON([]byte(doc))
+		if (len(c.paths) == 0) != (len(errs) == 0) {
+			t.Errorf("%s: schema violations at %q, validator reported %v", doc, c.paths, errs)
+			continue
+		}
+		for _, e := range errs {
+			if !slices.Contains(c.paths, e.Path) {
+				t.Errorf("%s: validator reported %q, schema rejects only %q", doc, e.Path, c.paths)
+			}
+		}
+	}
+}
+
+// schemaChecker проверяет документ по подмножеству JSON Schema 2020-12,
+// которое использует scenario.schema.json, и собирает пути нарушений.
+type schemaChecker struct {
+	root  map[string]any
+	paths []string
+}
+
+func (c *schemaChecker) check(schema map[string]any, doc any, path string) {
+	fail := func() { c.paths = append(c.paths, path) }
+	if ref, ok := schema["$ref"].(string); ok {
+		target := c.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			target = target[part].(map[string]any)
+		}
+		c.check(target, doc, path)
+	}
+	if typ, ok := schema["type"].(string); ok && !schemaType(typ, doc) {
+		fail()
+		return
+	}
+	if values, ok := schema["enum"].([]any); ok && !slices.Contains(values, doc) {
+		fail()
+	}
+	if v, ok := schema["const"]; ok && v != doc {
+		fail()
+	}
+	if n, ok := doc.(float64); ok {
+		if m, ok := schema["minimum"].(float64); ok && n < m {
+			fail()
+		}
+		if m, ok := schema["exclusiveMinimum"].(float64); ok && n <= m {
+			fail()
+		}
+	}
+	if s, ok := doc.(string); ok {
+		if m, ok := schema["minLength"].(float64); ok && float64(len([]rune(s))) < m {
+			fail()
+		}
+	}
+	if obj, ok := doc.(map[string]any); ok {
+		required, _ := schema["required"].([]any)
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				fail()
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for key, sub := range props {
+			if v, ok := obj[key]; ok {
+				c.check(sub.(map[string]any), v, path+"/"+key)
+			}
+		}
+	}
+	if list, ok := doc.([]any); ok {
+		if m, ok := schema["minItems"].(float64); ok && float64(len(list)) < m {
+			fail()
+		}
+		if m, ok := schema["maxItems"].(float64); ok && float64(len(list)) > m {
+			fail()
+		}
+		prefix, _ := schema["prefixItems"].([]any)
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range list {
+			switch itemPath := path + "/" + strconv.Itoa(i); {
+			case i < len(prefix):
+				c.check(prefix[i].(map[string]any), item, itemPath)
+			case items != nil:
+				c.check(items, item, itemPath)
+			}
+		}
+	}
+	allOf, _ := schema["allOf"].([]any)
+	for _, sub := range allOf {
+		c.check(sub.(map[string]any), doc, path)
+	}
+	if cond, ok := schema["if"].(map[string]any); ok {
+		probe := &schemaChecker{root: c.root}
+		probe.check(cond, doc, path)
+		if then, ok := schema["then"].(map[string]any); ok && len(probe.paths) == 0 {
+			c.check(then, doc, path)
+		}
+	}
+}
+
+func schemaType(typ string, v any) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	}
+	return false
+}
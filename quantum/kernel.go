@@ -0,0 +1,57 @@
+package quantum
+
+import "math"
+
+// Kernel задаёт вес взаимодействия пары клеток: совместный вес пары
+// при измерении равен p1·p2·kernel(c1, c2).
+// В режиме Toroidal мир передаёт вместо c2 её образ, ближайший к c1, поэтому
+// ядра по евклидову расстоянию учитывают замыкание.
+type Kernel func(c1, c2 [2]int) float64
+
+// ExactKernel — взаимодействие только в совпадающих клетках
+// (поведение World.MeasureInteraction).
+func ExactKernel(c1, c2 [2]int) float64 {
+	if c1 == c2 {
+		return 1
+	}
+	return 0
+}
+
+// GaussianKernel возвращает ядро exp(-d²/(2σ²)) по евклидову расстоянию d.
+// Неположительная sigma даёт ExactKernel.
+func GaussianKernel(sigma float64) Kernel {
+	if sigma <= 0 {
+		return ExactKernel
+	}
+	return func(c1, c2 [2]int) float64 {
+		d := euclid(c1, c2)
+		return math.Exp(-d * d / (2 * sigma * sigma))
+	}
+}
+
+// LinearFalloffKernel возвращает ядро 1 - d/maxDist, равное нулю при d >= maxDist.
+// Неположительный maxDist даёт ExactKernel.
+func LinearFalloffKernel(maxDist float64) Kernel {
+	if maxDist <= 0 {
+		return ExactKernel
+	}
+	return func(c1, c2 [2]int) float64 {
+		return max(0, 1-euclid(c1, c2)/maxDist)
+	}
+}
+
+func euclid(c1, c2 [2]int) float64 {
+	return math.Hypot(float64(c1[0]-c2[0]), float64(c1[1]-c2[1]))
+}
+
+// MeasureInteractionKernel — взаимодействие с произвольным ядром: каждый
+// объект получает маргинал совместного веса p1·p2·kernel(c1,c2) и
+// коллапсирует по нему. nil означает ExactKernel; MeasureInteraction
+// эквивалентен вызову с ExactKernel, но перебирает только общие клетки.
+// Результат — как у MeasureInteraction.
+func (w *World) MeasureInteractionKernel(obj1, obj2 *QuantumObject, kernel Kernel) bool {
+	if kernel == nil {
+		return w.MeasureInteraction(obj1, obj2)
+	}
+	return w.measure("MeasureInteractionKernel", obj1, obj2, w.pairJoint(kernel))
+}
@@ -0,0 +1,53 @@
+package quantum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBuiltinKernels(t *testing.T) {
+	a, b := [2]int{0, 0}, [2]int{3, 4}
+	if ExactKernel(a, a) != 1 || ExactKernel(a, b) != 0 {
+		t.Error("ExactKernel should match only equal cells")
+	}
+	if k := GaussianKernel(5)(a, b); math.Abs(k-math.Exp(-0.5)) > 1e-12 {
+		t.Errorf("GaussianKernel at d=σ should be e^-½, got %f", k)
+	}
+	if k := LinearFalloffKernel(10)(a, b); math.Abs(k-0.5) > 1e-12 {
+		t.Errorf("LinearFalloffKernel at half range should be 0.5, got %f", k)
+	}
+	if LinearFalloffKernel(5)(a, b) != 0 {
+		t.Error("LinearFalloffKernel should vanish at maxDist")
+	}
+}
+
+func TestMeasureInteractionKernel(t *testing.T) {
+	world := NewWorldWithRand(10, 10, rand.New(rand.NewSource(8)))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {9, 9}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 0}: 1})
+	world.MeasureInteractionKernel(a, b, LinearFalloffKernel(2))
+	if a.FinalCoord != [2]int{0, 0} || b.FinalCoord != [2]int{1, 0} {
+		t.Errorf("only the nearby cell can interact, got %v %v", a, b)
+	}
+
+	c := NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1})
+	d := NewQuantumObject("D", map[[2]int]float64{{1, 0}: 1})
+	world.MeasureInteractionKernel(c, d, nil)
+	if c.IsCollapsed || d.IsCollapsed {
+		t.Error("nil kernel should fall back to exact co-location")
+	}
+}
+
+func TestMeasureInteractionKernelToroidal(t *testing.T) {
+	world := NewWorldWithRand(10, 10, rand.New(rand.NewSource(8)))
+	world.Boundary = Toroidal
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {5, 5}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{9, 0}: 1})
+	if !world.MeasureInteractionKernel(a, b, LinearFalloffKernel(2)) {
+		t.Fatal("cells across the seam should interact")
+	}
+	if a.FinalCoord != [2]int{0, 0} || b.FinalCoord != [2]int{9, 0} {
+		t.Errorf("only the wrap-around neighbour can interact, got %v %v", a, b)
+	}
+}
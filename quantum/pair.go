@@ -0,0 +1,119 @@
+package quantum
+
+import "slices"
+
+// EntangledPair — совместное распределение двух объектов по парам клеток.
+// Ключ Joint — (x1, y1, x2, y2); веса не обязаны быть нормированы.
+type EntangledPair struct {
+	First, Second *QuantumObject
+	Joint         map[[4]int]float64
+
+	world *World
+}
+
+// EntangleJoint строит совместное распределение obj1 и obj2 как произведение
+// их нормированных распределений, умноженное на correlation(c1, c2)
+// (nil — без модуляции, то есть независимые объекты). После этого коллапс
+// одного объекта заменяет распределение другого условным по совместному.
+// В отличие от Entangle связь задаётся весами, а не детерминированным соответствием.
+func (w *World) EntangleJoint(obj1, obj2 *QuantumObject, correlation func(c1, c2 [2]int) float64) *EntangledPair {
+	p1, p2 := obj1.probabilities(), obj2.probabilities()
+	joint := make(map[[4]int]float64)
+	for c1, a := range p1 {
+		for c2, b := range p2 {
+			weight := a * b
+			if correlation != nil {
+				weight *= correlation(c1, c2)
+			}
+			if weight > 0 {
+				joint[[4]int{c1[0], c1[1], c2[0], c2[1]}] = weight
+			}
+		}
+	}
+	pair := &EntangledPair{First: obj1, Second: obj2, Joint: joint, world: w}
+	obj1.OnCollapse(func(c [2]int) { pair.condition(obj2, c, true) })
+	obj2.OnCollapse(func(c [2]int) { pair.condition(obj1, c, false) })
+	return pair
+}
+
+// condition сужает распределение партнёра до условного при известной
+// координате c другого объекта; first сообщает, что c относится к First.
+func (p *EntangledPair) condition(partner *QuantumObject, c [2]int, first bool) {
+	cond := make(map[[2]int]float64)
+	for k, w := range p.Joint {
+		if first && k[0] == c[0] && k[1] == c[1] {
+			cond[[2]int{k[2], k[3]}] += w
+		}
+		if !first && k[2] == c[0] && k[3] == c[1] {
+			cond[[2]int{k[0], k[1]}] += w
+		}
+	}
+	if len(cond) == 0 {
+		return
+	}
+	partner.mu.Lock()
+	defer partner.mu.Unlock()
+	if partner.IsCollapsed {
+		return
+	}
+	partner.dist, partner.CoordDist = nil, cond
+	partner.normalizeLocked()
+}
+
+// Collapse выбирает совместный исход (c1, c2) из Joint и одновременно
+// коллапсирует First в c1 и Second в c2. Если оба объекта уже коллапсированы
+// или совместное распределение пусто, ничего не делает.
+func (p *EntangledPair) Collapse() {
+	var fallback *lockedRand
+	if p.world != nil {
+		fallback = p.world.rng
+	}
+	obj1, obj2 := p.First, p.Second
+	lockPair(obj1, obj2)
+	if (obj1.IsCollapsed && obj2.IsCollapsed) || len(p.Joint) == 0 {
+		unlockPair(obj1, obj2)
+		return
+	}
+	keys := make([][4]int, 0, len(p.Joint))
+	total := 0.0
+	for k, w := range p.Joint {
+		// уже известная координата оставляет только согласованные исходы
+		if obj1.IsCollapsed && (k[0] != obj1.FinalCoord[0] || k[1] != obj1.FinalCoord[1]) {
+			continue
+		}
+		if obj2.IsCollapsed && (k[2] != obj2.FinalCoord[0] || k[3] != obj2.FinalCoord[1]) {
+			continue
+		}
+		keys = append(keys, k)
+		total += w
+	}
+	if len(keys) == 0 {
+		unlockPair(obj1, obj2)
+		return
+	}
+	slices.SortFunc(keys, func(a, b [4]int) int { return slices.Compare(a[:], b[:]) })
+	r := drawFloat(obj1.rng, fallback) * total
+	chosen := keys[len(keys)-1]
+	cumulative := 0.0
+	for _, k := range keys {
+		cumulative += p.Joint[k]
+		if r <= cumulative {
+			chosen = k
+			break
+		}
+	}
+	settled1, settled2 := !obj1.IsCollapsed, !obj2.IsCollapsed
+	if settled1 {
+		obj1.settleLocked([2]int{chosen[0], chosen[1]})
+	}
+	if settled2 {
+		obj2.settleLocked([2]int{chosen[2], chosen[3]})
+	}
+	unlockPair(obj1, obj2)
+	if settled1 {
+		obj1.settled(fallback)
+	}
+	if settled2 {
+		obj2.settled(fallback)
+	}
+}
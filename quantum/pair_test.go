@@ -0,0 +1,45 @@
+package quantum
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// sameX — корреляция, допускающая только пары в одном столбце.
+func sameX(c1, c2 [2]int) float64 {
+	if c1[0] == c2[0] {
+		return 1
+	}
+	return 0
+}
+
+func TestEntangledPairCollapse(t *testing.T) {
+	world := NewWorldWithRand(3, 3, rand.New(rand.NewSource(2)))
+	for i := 0; i < 20; i++ {
+		a := NewQuantumObject("A", gridDist(3, 1))
+		b := NewQuantumObject("B", map[[2]int]float64{{0, 2}: 1, {1, 2}: 1, {2, 2}: 1})
+		pair := world.EntangleJoint(a, b, sameX)
+		pair.Collapse()
+		if !a.IsCollapsed || !b.IsCollapsed {
+			t.Fatal("pair collapse should fix both objects")
+		}
+		if a.FinalCoord[0] != b.FinalCoord[0] {
+			t.Fatalf("joint outcome should respect the correlation: %v %v", a, b)
+		}
+	}
+}
+
+func TestEntangledPairConditionsPartner(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 1}: 1, {1, 1}: 1, {1, 2}: 1})
+	world.EntangleJoint(a, b, sameX)
+
+	a.pin([2]int{1, 0}, nil)
+	if b.IsCollapsed {
+		t.Fatal("measuring one object should not collapse its partner")
+	}
+	if b.ProbabilityAt(0, 1) != 0 || b.ProbabilityAt(1, 1) != 0.5 || b.ProbabilityAt(1, 2) != 0.5 {
+		t.Errorf("partner should take the conditional distribution, got %v", b.CoordDist)
+	}
+}
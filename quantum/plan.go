@@ -0,0 +1,36 @@
+package quantum
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MeasurementStep — одно измерение плана: имена двух объектов.
+type MeasurementStep struct {
+	A, B string
+}
+
+// MeasurementPlan — упорядоченный список измерений, отделяющий описание
+// сценария от его выполнения.
+type MeasurementPlan []MeasurementStep
+
+// RunPlan выполняет MeasureInteraction для шагов плана по порядку; объекты
+// ищутся через Find. Шаги, в которых объекта нет в мире, пропускаются,
+// остальные выполняются. Возвращает nil или объединение ошибок по каждому
+// пропущенному шагу; ошибки оборачивают *ObjectNotFoundError.
+func (w *World) RunPlan(plan MeasurementPlan) error {
+	var errs []error
+	for i, step := range plan {
+		a, okA := w.Find(step.A)
+		b, okB := w.Find(step.B)
+		switch {
+		case !okA:
+			errs = append(errs, fmt.Errorf("step %d: %w", i, &ObjectNotFoundError{Name: step.A}))
+		case !okB:
+			errs = append(errs, fmt.Errorf("step %d: %w", i, &ObjectNotFoundError{Name: step.B}))
+		default:
+			w.MeasureInteraction(a, b)
+		}
+	}
+	return errors.Join(errs...)
+}
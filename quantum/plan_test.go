@@ -0,0 +1,35 @@
+package quantum
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunPlan(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	world.EnableEventLog(true)
+	for _, name := range []string{"john", "tree", "rock"} {
+		world.AddQuantumObject(NewQuantumObject(name, map[[2]int]float64{{1, 1}: 1, {2, 2}: 1}))
+	}
+	world.ClearHistory()
+
+	err := world.RunPlan(MeasurementPlan{{"rock", "john"}, {"ghost", "tree"}, {"tree", "john"}})
+	var nf *ObjectNotFoundError
+	if !errors.As(err, &nf) || nf.Name != "ghost" || !strings.Contains(err.Error(), "step 1") {
+		t.Errorf("missing object should be reported with its step, got %v", err)
+	}
+	var measured [][]string
+	for _, ev := range world.History() {
+		if ev.Kind == EventMeasure {
+			measured = append(measured, ev.Names)
+		}
+	}
+	if len(measured) != 2 || measured[0][0] != "rock" || measured[1][0] != "tree" {
+		t.Errorf("plan should run the remaining steps in order, got %v", measured)
+	}
+
+	if err := world.RunPlan(nil); err != nil {
+		t.Errorf("empty plan should succeed, got %v", err)
+	}
+}
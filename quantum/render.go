@@ -0,0 +1,203 @@
+package quantum
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// asciiShades — символы возрастающей плотности для RenderASCII.
+const asciiShades = " .:-=+*#%@"
+
+// CollapsedMarker обозначает FinalCoord коллапсированного объекта в RenderASCII.
+const CollapsedMarker = 'X'
+
+// RenderASCII рисует нормированное распределение на сетке width×height:
+// строка соответствует y, столбец — x, плотность символа пропорциональна
+// вероятности клетки относительно максимальной. Коллапсированный объект
+// изображается одним маркером CollapsedMarker в FinalCoord.
+// Клетки вне сетки пропускаются.
+func (q *QuantumObject) RenderASCII(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	grid := make([][]byte, height)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(" ", width))
+	}
+	inside := func(c [2]int) bool {
+		return c[0] >= 0 && c[0] < width && c[1] >= 0 && c[1] < height
+	}
+
+	q.mu.RLock()
+	collapsed, final := q.IsCollapsed, q.FinalCoord
+	q.mu.RUnlock()
+	if collapsed {
+		if inside(final) {
+			grid[final[1]][final[0]] = CollapsedMarker
+		}
+		return joinRows(grid)
+	}
+
+	probs := q.probabilities()
+	peak := 0.0
+	for c, p := range probs {
+		if inside(c) {
+			peak = max(peak, p)
+		}
+	}
+	if peak > 0 {
+		top := float64(len(asciiShades) - 1)
+		for c, p := range probs {
+			if !inside(c) || p <= 0 {
+				continue
+			}
+			// любая ненулевая вероятность видна хотя бы как '.'
+			level := max(1, int(math.Round(p/peak*top)))
+			grid[c[1]][c[0]] = asciiShades[level]
+		}
+	}
+	return joinRows(grid)
+}
+
+func joinRows(grid [][]byte) string {
+	var b strings.Builder
+	for _, row := range grid {
+		b.Write(row)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Символы World.RenderASCII.
+const (
+	uncoveredCell = '?' // ни один объект не имеет веса в клетке
+	collapsedCell = '*' // FinalCoord коллапсированного объекта
+	tiedCell      = '+' // несколько объектов с равной наибольшей вероятностью
+)
+
+// densityBlocks — квартили суммарной вероятности относительно максимальной.
+var densityBlocks = []rune("░▒▓█")
+
+// RenderASCII выводит две сетки Width×Height (строка — y, столбец — x),
+// разделённые пустой строкой. В первой клетка помечена первой буквой имени
+// объекта с наибольшей вероятностью в ней, во второй — символом ░▒▓█ по
+// квартилю суммарной вероятности несколлапсированных объектов относительно
+// максимальной клетки. В обеих сетках '*' отмечает FinalCoord коллапсированных
+// объектов, '?' — клетки без веса, '+' в первой — равенство нескольких объектов.
+func (w *World) RenderASCII(out io.Writer) error {
+	width, height := max(w.Width, 0), max(w.Height, 0)
+	type cell struct {
+		best      float64
+		owner     rune
+		tied      bool
+		sum       float64
+		collapsed bool
+	}
+	grid := make([][]cell, height)
+	for y := range grid {
+		grid[y] = make([]cell, width)
+	}
+	inside := func(c [2]int) bool {
+		return c[0] >= 0 && c[0] < width && c[1] >= 0 && c[1] < height
+	}
+	for _, obj := range w.objects() {
+		obj.mu.RLock()
+		collapsed, final := obj.IsCollapsed, obj.FinalCoord
+		obj.mu.RUnlock()
+		if collapsed {
+			if inside(final) {
+				grid[final[1]][final[0]].collapsed = true
+			}
+			continue
+		}
+		initial, _ := utf8.DecodeRuneInString(obj.Name)
+		for c, p := range obj.probabilities() {
+			if !inside(c) || p <= 0 {
+				continue
+			}
+			g := &grid[c[1]][c[0]]
+			g.sum += p
+			switch {
+			case p > g.best:
+				g.best, g.owner, g.tied = p, initial, false
+			case p == g.best:
+				g.tied = true
+			}
+		}
+	}
+	peak := 0.0
+	for _, row := range grid {
+		for _, g := range row {
+			peak = max(peak, g.sum)
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range grid {
+		for _, g := range row {
+			switch {
+			case g.collapsed:
+				b.WriteRune(collapsedCell)
+			case g.sum == 0:
+				b.WriteRune(uncoveredCell)
+			case g.tied:
+				b.WriteRune(tiedCell)
+			default:
+				b.WriteRune(g.owner)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	for _, row := range grid {
+		for _, g := range row {
+			switch {
+			case g.collapsed:
+				b.WriteRune(collapsedCell)
+			case g.sum == 0:
+				b.WriteRune(uncoveredCell)
+			default:
+				q := min(int(math.Ceil(g.sum/peak*4))-1, 3)
+				b.WriteRune(densityBlocks[max(q, 0)])
+			}
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// barCells — число клеток, выводимых RenderBar, и ширина самой длинной полосы.
+const (
+	barCells = 20
+	barWidth = 40
+)
+
+// RenderBar выводит горизонтальную диаграмму 20 самых вероятных клеток
+// по убыванию вероятности (при равенстве — по координате): строка
+// "(x, y) ████ 0.1234", длина полосы пропорциональна вероятности.
+func (q *QuantumObject) RenderBar(out io.Writer) error {
+	probs := q.probabilities()
+	coords := sortedCoords(probs)
+	slices.SortStableFunc(coords, func(a, b [2]int) int {
+		return cmp.Compare(probs[b], probs[a])
+	})
+	coords = coords[:min(len(coords), barCells)]
+	if len(coords) == 0 {
+		return nil
+	}
+	peak := probs[coords[0]]
+	for _, c := range coords {
+		label := fmt.Sprintf("(%d, %d)", c[0], c[1])
+		bar := strings.Repeat("█", max(1, int(math.Round(probs[c]/peak*barWidth))))
+		if _, err := fmt.Fprintf(out, "%-12s %s %.4f\n", label, bar, probs[c]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package quantum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderASCII(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 4, {2, 1}: 1, {9, 9}: 5})
+	got := obj.RenderASCII(3, 2)
+	rows := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(rows) != 2 || len(rows[0]) != 3 {
+		t.Fatalf("expected a 3x2 grid, got %q", got)
+	}
+	if rows[0][0] != '@' {
+		t.Errorf("peak cell should use the densest shade, got %q", rows[0][0])
+	}
+	if rows[1][2] == ' ' || rows[1][2] == '@' {
+		t.Errorf("weaker cell should use an intermediate shade, got %q", rows[1][2])
+	}
+	if rows[0][1] != ' ' {
+		t.Errorf("empty cell should be blank, got %q", rows[0][1])
+	}
+
+	obj.Collapse()
+	got = obj.RenderASCII(10, 10)
+	if strings.Count(got, string(CollapsedMarker)) != 1 {
+		t.Errorf("collapsed object should show a single marker:\n%s", got)
+	}
+	if strings.Trim(got, " \nX") != "" {
+		t.Errorf("collapsed render should contain only the marker:\n%s", got)
+	}
+}
+
+func TestWorldRenderASCII(t *testing.T) {
+	world := NewWorld(WithSize(4, 2))
+	world.AddQuantumObject(NewQuantumObject("alpha", map[[2]int]float64{{0, 0}: 3, {1, 0}: 1}))
+	world.AddQuantumObject(NewQuantumObject("beta", map[[2]int]float64{{1, 0}: 1, {2, 1}: 1}))
+	c := NewQuantumObject("c", map[[2]int]float64{{3, 1}: 1})
+	c.Collapse()
+	world.AddQuantumObject(c)
+
+	var buf strings.Builder
+	if err := world.RenderASCII(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "ab??\n??b*\n\n██??\n??▓*\n"
+	if buf.String() != want {
+		t.Errorf("unexpected render:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRenderBar(t *testing.T) {
+	obj := NewQuantumObject("A", gridDist(6, 6))
+	var buf strings.Builder
+	if err := obj.RenderBar(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != barCells {
+		t.Fatalf("expected %d bars, got %d", barCells, len(lines))
+	}
+	if strings.Count(lines[0], "█") != barWidth {
+		t.Errorf("most likely cell should get the full bar: %q", lines[0])
+	}
+	if strings.Count(lines[0], "█") < strings.Count(lines[len(lines)-1], "█") {
+		t.Error("bars should be sorted by decreasing probability")
+	}
+}
@@ -0,0 +1,186 @@
+package quantum
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Общая часть загрузчиков сценариев: LoadWorldFromYAML и LoadWorldFromJSON
+// разбирают документ в map[string]any и строят мир одинаково.
+
+// scenarioObject — поля одного объекта сценария с проверкой типов.
+type scenarioObject struct {
+	name   string
+	fields map[string]any
+}
+
+func (o scenarioObject) float(key string) (float64, error) {
+	v, ok := o.fields[key]
+	if !ok {
+		return 0, fmt.Errorf("scenario: object %q: missing %q", o.name, key)
+	}
+	f, err := scenarioFloat(v)
+	if err != nil {
+		return 0, fmt.Errorf("scenario: object %q: %s: %w", o.name, key, err)
+	}
+	return f, nil
+}
+
+func (o scenarioObject) int(key string) (int, error) {
+	f, err := o.float(key)
+	if err == nil && f != float64(int(f)) {
+		err = fmt.Errorf("scenario: object %q: %s should be an integer, got %v", o.name, key, f)
+	}
+	return int(f), err
+}
+
+// scenarioFloat принимает число документа; YAML к этому моменту уже
+// преобразован в JSON, поэтому числа приходят как float64.
+func scenarioFloat(v any) (float64, error) {
+	if f, ok := v.(float64); ok {
+		return f, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %v", v)
+}
+
+func buildScenario(root map[string]any) (*World, error) {
+	spec, ok := root["world"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("scenario: missing world section")
+	}
+	ws := scenarioObject{name: "world", fields: spec}
+	width, err := ws.int("width")
+	if err != nil {
+		return nil, err
+	}
+	height, err := ws.int("height")
+	if err != nil {
+		return nil, err
+	}
+	world := NewWorld(WithSize(width, height))
+	if b, ok := spec["boundary"]; ok {
+		mode, err := parseBoundary(b)
+		if err != nil {
+			return nil, err
+		}
+		world.Boundary = mode
+	}
+
+	objects, _ := root["objects"].([]any)
+	for i, item := range objects {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("scenario: objects[%d] should be a mapping", i)
+		}
+		name, _ := fields["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("scenario: objects[%d]: missing name", i)
+		}
+		obj, err := buildScenarioObject(scenarioObject{name: name, fields: fields}, width, height)
+		if err != nil {
+			return nil, err
+		}
+		world.AddQuantumObject(obj)
+		if fields["collapsed"] == true {
+			at, _ := fields["at"].([]any)
+			if len(at) != 2 {
+				return nil, fmt.Errorf("scenario: object %q: collapsed requires at: [x, y]", name)
+			}
+			x, err1 := scenarioFloat(at[0])
+			y, err2 := scenarioFloat(at[1])
+			if err := cmp.Or(err1, err2); err != nil {
+				return nil, fmt.Errorf("scenario: object %q: at: %w", name, err)
+			}
+			c := [2]int{int(x), int(y)}
+			if obj.ProbabilityAt(c[0], c[1]) <= 0 {
+				return nil, fmt.Errorf("scenario: object %q: cannot collapse at (%d, %d) with zero probability", name, c[0], c[1])
+			}
+			obj.pin(c, world.rng)
+		}
+	}
+
+	interactions, _ := root["interactions"].([]any)
+	for i, item := range interactions {
+		step, _ := item.(map[string]any)
+		pair, _ := step["measure"].([]any)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("scenario: interactions[%d]: expected measure: [a, b]", i)
+		}
+		var objs [2]*QuantumObject
+		for j, v := range pair {
+			name, _ := v.(string)
+			obj, ok := world.Find(name)
+			if !ok {
+				return nil, fmt.Errorf("scenario: interactions[%d]: %w", i, &ObjectNotFoundError{Name: name})
+			}
+			objs[j] = obj
+		}
+		world.MeasureInteraction(objs[0], objs[1])
+	}
+	return world, nil
+}
+
+func parseBoundary(v any) (BoundaryMode, error) {
+	for _, mode := range []BoundaryMode{Bounded, Toroidal, Reflecting} {
+		if v == mode.String() {
+			return mode, nil
+		}
+	}
+	return Bounded, fmt.Errorf("scenario: unknown boundary %v", v)
+}
+
+// buildScenarioObject вызывает конструктор, соответствующий полю type.
+func buildScenarioObject(o scenarioObject, width, height int) (*QuantumObject, error) {
+	typ, _ := o.fields["type"].(string)
+	switch typ {
+	case "gaussian":
+		cx, err1 := o.int("cx")
+		cy, err2 := o.int("cy")
+		sigma, err3 := o.float("sigma")
+		if err := cmp.Or(err1, err2, err3); err != nil {
+			return nil, err
+		}
+		return NewGaussianQuantumObject(o.name, cx, cy, sigma, width, height), nil
+	case "uniform":
+		return NewQuantumObject(o.name, UniformDistribution(width, height)), nil
+	case "point":
+		x, err1 := o.int("x")
+		y, err2 := o.int("y")
+		if err := cmp.Or(err1, err2); err != nil {
+			return nil, err
+		}
+		return NewQuantumObject(o.name, map[[2]int]float64{{x, y}: 1}), nil
+	case "ring":
+		cx, err1 := o.int("cx")
+		cy, err2 := o.int("cy")
+		radius, err3 := o.int("radius")
+		w, err4 := o.int("width")
+		if err := cmp.Or(err1, err2, err3, err4); err != nil {
+			return nil, err
+		}
+		return NewRingQuantumObject(o.name, cx, cy, radius, w, width, height), nil
+	case "cells":
+		cells, _ := o.fields["cells"].([]any)
+		if len(cells) == 0 {
+			return nil, fmt.Errorf("scenario: object %q: cells should be a non-empty list", o.name)
+		}
+		dist := make(map[[2]int]float64, len(cells))
+		for _, cell := range cells {
+			v, _ := cell.([]any)
+			if len(v) != 3 {
+				return nil, fmt.Errorf("scenario: object %q: cell %v should be [x, y, weight]", o.name, cell)
+			}
+			x, err1 := scenarioFloat(v[0])
+			y, err2 := scenarioFloat(v[1])
+			w, err3 := scenarioFloat(v[2])
+			if err := cmp.Or(err1, err2, err3); err != nil {
+				return nil, fmt.Errorf("scenario: object %q: cell %v: %w", o.name, cell, err)
+			}
+			dist[[2]int{int(x), int(y)}] += w
+		}
+		return NewQuantumObject(o.name, dist), nil
+	case "":
+		return nil, fmt.Errorf("scenario: object %q: missing type", o.name)
+	}
+	return nil, fmt.Errorf("scenario: object %q: unknown type %q (want gaussian, uniform, point, ring or cells)", o.name, typ)
+}
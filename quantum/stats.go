@@ -0,0 +1,532 @@
+package quantum
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// total возвращает сумму весов распределения. Вызывается под блокировкой q.
+func (q *QuantumObject) total() float64 {
+	if d := q.denseLocked(); d != nil {
+		return d.Total()
+	}
+	total := 0.0
+	for _, w := range q.CoordDist {
+		total += w
+	}
+	return total
+}
+
+// ProbabilityAt возвращает нормированную вероятность нахождения объекта в (x,y),
+// не изменяя CoordDist. Для коллапсированного объекта — 1 в FinalCoord и 0 в остальных точках.
+func (q *QuantumObject) ProbabilityAt(x, y int) float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	c := [2]int{x, y}
+	if q.IsCollapsed {
+		if c == q.FinalCoord {
+			return 1.0
+		}
+		return 0.0
+	}
+	total := q.total()
+	if total <= 0 {
+		return 0.0
+	}
+	if d := q.denseLocked(); d != nil {
+		return d.Weight(x, y) / total
+	}
+	return q.CoordDist[c] / total
+}
+
+// Entropy возвращает энтропию Шеннона нормированного распределения в битах.
+// Клетки с нулевым весом пропускаются; коллапсированный объект имеет энтропию 0.
+func (q *QuantumObject) Entropy() float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.IsCollapsed {
+		return 0
+	}
+	total := q.total()
+	if total <= 0 {
+		return 0
+	}
+	h := 0.0
+	for _, w := range q.coordsLocked() {
+		if w > 0 {
+			p := w / total
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// Entropies возвращает энтропию каждого объекта мира по имени.
+// При совпадении имён в результат попадает последний объект.
+func (w *World) Entropies() map[string]float64 {
+	objs := w.objects()
+	out := make(map[string]float64, len(objs))
+	for _, obj := range objs {
+		out[obj.Name] = obj.Entropy()
+	}
+	return out
+}
+
+// MarginalX возвращает нормированное маргинальное распределение по оси x.
+func (q *QuantumObject) MarginalX() map[int]float64 {
+	return q.marginal(0)
+}
+
+// MarginalY возвращает нормированное маргинальное распределение по оси y.
+func (q *QuantumObject) MarginalY() map[int]float64 {
+	return q.marginal(1)
+}
+
+// marginal суммирует совместное распределение по всем осям, кроме axis.
+// Для коллапсированного объекта результат — дельта в компоненте FinalCoord.
+func (q *QuantumObject) marginal(axis int) map[int]float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.IsCollapsed {
+		return map[int]float64{q.FinalCoord[axis]: 1.0}
+	}
+	out := make(map[int]float64)
+	total := q.total()
+	if total <= 0 {
+		return out
+	}
+	for c, w := range q.coordsLocked() {
+		out[c[axis]] += w / total
+	}
+	return out
+}
+
+// IsFactorized сообщает, раскладывается ли распределение в произведение
+// маргиналов: |p(x,y) - p(x)·p(y)| <= tol для всех x и y из их носителей.
+// Такой объект локализован по каждой оси независимо от другой.
+func (q *QuantumObject) IsFactorized(tol float64) bool {
+	probs := q.probabilities()
+	mx, my := q.MarginalX(), q.MarginalY()
+	for x, px := range mx {
+		for y, py := range my {
+			if math.Abs(probs[[2]int{x, y}]-px*py) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// probabilities возвращает нормированную копию распределения, не изменяя объект.
+// Для коллапсированного объекта это дельта в FinalCoord.
+func (q *QuantumObject) probabilities() map[[2]int]float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.IsCollapsed {
+		return map[[2]int]float64{q.FinalCoord: 1.0}
+	}
+	out := make(map[[2]int]float64, len(q.CoordDist))
+	total := q.total()
+	if total <= 0 {
+		return out
+	}
+	for c, w := range q.coordsLocked() {
+		if w > 0 {
+			out[c] = w / total
+		}
+	}
+	return out
+}
+
+// KLDivergence возвращает расхождение Кульбака–Лейблера D(p‖q) в битах
+// (в тех же единицах, что и Entropy). Оба распределения нормируются на копиях.
+// Если q равно нулю там, где p положительно, результат — +Inf.
+// Коллапсированный объект считается дельтой в FinalCoord, поэтому
+// KLDivergence(после, до) — информация, полученная при измерении: -log₂ p(FinalCoord).
+func KLDivergence(p, q *QuantumObject) float64 {
+	pp, qp := p.probabilities(), q.probabilities()
+	d := 0.0
+	for c, pv := range pp {
+		qv := qp[c]
+		if qv == 0 {
+			return math.Inf(1)
+		}
+		d += pv * math.Log2(pv/qv)
+	}
+	return d
+}
+
+// HellingerDistance возвращает расстояние Хеллингера между нормированными
+// распределениями: (1/√2)·√Σ(√p1 - √p2)² по объединению носителей.
+// В отличие от KLDivergence оно симметрично, ограничено отрезком [0, 1]
+// и конечно при непересекающихся носителях (тогда равно 1), поэтому
+// подходит для проверки, что объекты сошлись к одному распределению.
+func HellingerDistance(obj1, obj2 *QuantumObject) float64 {
+	p1, p2 := obj1.probabilities(), obj2.probabilities()
+	sum := 0.0
+	for c, p := range p1 {
+		d := math.Sqrt(p) - math.Sqrt(p2[c])
+		sum += d * d
+	}
+	for c, p := range p2 {
+		if _, ok := p1[c]; !ok {
+			sum += p
+		}
+	}
+	return min(math.Sqrt(sum/2), 1)
+}
+
+// SlicedWassersteinDistance приближает расстояние Вассерштейна W1 (минимальную
+// работу по перемещению массы) между нормированными распределениями:
+// клетки проецируются на nSlices случайных направлений, для каждой проекции
+// W1 считается точно как ∫|F1 - F2| по одномерным функциям распределения,
+// результат усредняется. В отличие от KLDivergence и HellingerDistance
+// учитывает геометрию: для точечных объектов на расстоянии d результат близок
+// к 2d/π. Расстояния евклидовы, без учёта замыкания сетки. rng == nil —
+// глобальный генератор math/rand; nSlices < 1 считается равным 1.
+// Пустое распределение даёт 0.
+func SlicedWassersteinDistance(obj1, obj2 *QuantumObject, nSlices int, rng *rand.Rand) float64 {
+	p1, p2 := obj1.probabilities(), obj2.probabilities()
+	if len(p1) == 0 || len(p2) == 0 {
+		return 0
+	}
+	type point struct {
+		t, mass float64 // проекция и масса со знаком: + для obj1, - для obj2
+	}
+	points := make([]point, 0, len(p1)+len(p2))
+	nSlices = max(nSlices, 1)
+	total := 0.0
+	for range nSlices {
+		theta := math.Pi * randFloat(rng)
+		cos, sin := math.Cos(theta), math.Sin(theta)
+		points = points[:0]
+		for c, p := range p1 {
+			points = append(points, point{float64(c[0])*cos + float64(c[1])*sin, p})
+		}
+		for c, p := range p2 {
+			points = append(points, point{float64(c[0])*cos + float64(c[1])*sin, -p})
+		}
+		slices.SortFunc(points, func(a, b point) int { return cmp.Compare(a.t, b.t) })
+		diff := 0.0 // F1 - F2 слева от текущей точки
+		for i, pt := range points {
+			if i > 0 {
+				total += math.Abs(diff) * (pt.t - points[i-1].t)
+			}
+			diff += pt.mass
+		}
+	}
+	return total / float64(nSlices)
+}
+
+// Correlation возвращает коэффициент корреляции Пирсона между нормированными
+// распределениями obj1 и obj2 как функциями клетки:
+// (E[f·g] - E[f]·E[g]) / (σf·σg), среднее берётся по объединению носителей.
+// Близкое к +1 значение означает, что масса объектов сосредоточена в одних клетках,
+// к -1 — в разных. Если одно из распределений постоянно на этих клетках, результат 0.
+func Correlation(obj1, obj2 *QuantumObject) float64 {
+	f, g := obj1.probabilities(), obj2.probabilities()
+	cells := make(map[[2]int]struct{}, len(f)+len(g))
+	for c := range f {
+		cells[c] = struct{}{}
+	}
+	for c := range g {
+		cells[c] = struct{}{}
+	}
+	if len(cells) == 0 {
+		return 0
+	}
+	n := float64(len(cells))
+	var ef, eg, efg, eff, egg float64
+	for c := range cells {
+		ef += f[c] / n
+		eg += g[c] / n
+		efg += f[c] * g[c] / n
+		eff += f[c] * f[c] / n
+		egg += g[c] * g[c] / n
+	}
+	varF, varG := eff-ef*ef, egg-eg*eg
+	if varF <= 0 || varG <= 0 {
+		return 0
+	}
+	return (efg - ef*eg) / math.Sqrt(varF*varG)
+}
+
+// Correlation возвращает коэффициенты корреляции Пирсона координат x и y
+// двух объектов по их совместному распределению. В отличие от одноимённой
+// функции пакета, сравнивающей распределения по клеткам, здесь коррелируют
+// сами положения. Совместное распределение строится так:
+//   - если объекты связаны Entangle, пара клеток (c, pairing(c)) получает
+//     вероятность исходного объекта в c;
+//   - иначе берётся совместное распределение при совпадении клеток, как
+//     после MeasureInteraction: пара (c, c) получает вес p1(c)·p2(c),
+//     нормированный на сумму по общим клеткам.
+//
+// Поэтому объекты с общими клетками дают 1 по каждой оси, где общий носитель
+// разбросан, а объекты без общих клеток — 0. Если координата не разбросана
+// (например, единственная общая клетка), корреляция по этой оси равна 0.
+// Для весовой связи EntangleJoint см. EntangledPair.Correlation.
+func (w *World) Correlation(obj1, obj2 *QuantumObject) (float64, float64) {
+	if pairing, ok := pairingBetween(obj1, obj2); ok {
+		return correlateJoint(pairedJoint(obj1, pairing, false))
+	}
+	if pairing, ok := pairingBetween(obj2, obj1); ok {
+		return correlateJoint(pairedJoint(obj2, pairing, true))
+	}
+	return correlateJoint(colocatedJoint(obj1, obj2))
+}
+
+// colocatedJoint — совместное распределение при условии, что объекты
+// находятся в одной клетке: вес пары (c, c) равен p1(c)·p2(c).
+func colocatedJoint(obj1, obj2 *QuantumObject) map[[4]int]float64 {
+	p2 := obj2.probabilities()
+	joint := make(map[[4]int]float64)
+	for c, p := range obj1.probabilities() {
+		if q := p2[c]; q > 0 {
+			joint[[4]int{c[0], c[1], c[0], c[1]}] = p * q
+		}
+	}
+	return joint
+}
+
+// Correlation возвращает корреляции Пирсона координат x и y объектов пары
+// по весам Joint.
+func (p *EntangledPair) Correlation() (float64, float64) {
+	return correlateJoint(p.Joint)
+}
+
+// pairingBetween ищет связь Entangle, в которой from — исходный объект, а to — образ.
+func pairingBetween(from, to *QuantumObject) (func([2]int) [2]int, bool) {
+	from.mu.RLock()
+	defer from.mu.RUnlock()
+	for _, l := range from.entangled {
+		if l.partner == to && !l.inverse {
+			return l.pairing, true
+		}
+	}
+	return nil, false
+}
+
+// pairedJoint — совместное распределение детерминированной связи: вероятность
+// исходного объекта src в c переносится на пару (c, pairing(c)); swap меняет
+// объекты в паре местами.
+func pairedJoint(src *QuantumObject, pairing func([2]int) [2]int, swap bool) map[[4]int]float64 {
+	joint := make(map[[4]int]float64)
+	for c, p := range src.probabilities() {
+		d := pairing(c)
+		if swap {
+			c, d = d, c
+		}
+		joint[[4]int{c[0], c[1], d[0], d[1]}] += p
+	}
+	return joint
+}
+
+// correlateJoint вычисляет корреляции Пирсона x1 с x2 и y1 с y2 по
+// совместному распределению с ключами (x1, y1, x2, y2).
+func correlateJoint(joint map[[4]int]float64) (float64, float64) {
+	var total float64
+	var mean, sq [4]float64
+	var cross [2]float64
+	for k, w := range joint {
+		total += w
+		for i, v := range k {
+			mean[i] += w * float64(v)
+			sq[i] += w * float64(v) * float64(v)
+		}
+		cross[0] += w * float64(k[0]) * float64(k[2])
+		cross[1] += w * float64(k[1]) * float64(k[3])
+	}
+	if total <= 0 {
+		return 0, 0
+	}
+	pearson := func(a, b int, ab float64) float64 {
+		ma, mb := mean[a]/total, mean[b]/total
+		va, vb := sq[a]/total-ma*ma, sq[b]/total-mb*mb
+		if va <= 1e-12 || vb <= 1e-12 {
+			return 0
+		}
+		return (ab/total - ma*mb) / math.Sqrt(va*vb)
+	}
+	return pearson(0, 2, cross[0]), pearson(1, 3, cross[1])
+}
+
+// MostLikely возвращает моду распределения — клетку с наибольшей нормированной
+// вероятностью — и саму вероятность. При равенстве выбирается наименьшая
+// координата в порядке (x, y). Для коллапсированного объекта — FinalCoord и 1.
+// Пустое распределение даёт нулевую координату и 0.
+func (q *QuantumObject) MostLikely() ([2]int, float64) {
+	probs := q.probabilities()
+	var best [2]int
+	bestP := 0.0
+	for _, c := range sortedCoords(probs) {
+		if p := probs[c]; p > bestP {
+			best, bestP = c, p
+		}
+	}
+	return best, bestP
+}
+
+// ExpectedPosition возвращает математическое ожидание координат (центр масс)
+// нормированного распределения. Результат может лежать между клетками сетки
+// и не округляется до целых. Для коллапсированного объекта — FinalCoord.
+func (q *QuantumObject) ExpectedPosition() (float64, float64) {
+	ex, ey := 0.0, 0.0
+	for c, p := range q.probabilities() {
+		ex += p * float64(c[0])
+		ey += p * float64(c[1])
+	}
+	return ex, ey
+}
+
+// Variance возвращает дисперсии координат x и y нормированного распределения
+// (вокруг ExpectedPosition). Для коллапсированного объекта — (0, 0).
+func (q *QuantumObject) Variance() (float64, float64) {
+	probs := q.probabilities()
+	ex, ey := 0.0, 0.0
+	for c, p := range probs {
+		ex += p * float64(c[0])
+		ey += p * float64(c[1])
+	}
+	vx, vy := 0.0, 0.0
+	// двухпроходная формула вместо E[x²]-E[x]² не теряет точность,
+	// когда почти вся масса сосредоточена в одной клетке
+	for c, p := range probs {
+		dx, dy := float64(c[0])-ex, float64(c[1])-ey
+		vx += p * dx * dx
+		vy += p * dy * dy
+	}
+	return max(vx, 0), max(vy, 0)
+}
+
+// StdDev возвращает стандартные отклонения координат x и y.
+func (q *QuantumObject) StdDev() (float64, float64) {
+	vx, vy := q.Variance()
+	return math.Sqrt(vx), math.Sqrt(vy)
+}
+
+// Sample выполняет n независимых пробных коллапсов и возвращает гистограмму
+// исходов, не изменяя объект. Исходы берутся из rng, а при nil — из генератора
+// объекта, мира или глобального, как при Collapse. Клетки выбираются той же
+// обратной функцией распределения в порядке sortedCoords. Коллапсированный
+// объект даёт все n исходов в FinalCoord; пустое распределение — пустую гистограмму.
+func (q *QuantumObject) Sample(n int, rng *rand.Rand) map[[2]int]int {
+	q.mu.RLock()
+	collapsed, final := q.IsCollapsed, q.FinalCoord
+	var coords [][2]int
+	var cumulative []float64
+	total := 0.0
+	dist := q.coordsLocked()
+	for _, c := range sortedCoords(dist) {
+		if w := dist[c]; w > 0 {
+			total += w
+			coords = append(coords, c)
+			cumulative = append(cumulative, total)
+		}
+	}
+	own, fallback := q.rng, (*lockedRand)(nil)
+	if q.world != nil {
+		fallback = q.world.rng
+	}
+	q.mu.RUnlock()
+
+	hist := make(map[[2]int]int)
+	if n <= 0 {
+		return hist
+	}
+	if collapsed {
+		hist[final] = n
+		return hist
+	}
+	if len(coords) == 0 {
+		return hist
+	}
+	draw := func() float64 { return drawFloat(own, fallback) }
+	if rng != nil {
+		draw = rng.Float64
+	}
+	for range n {
+		i := sort.SearchFloat64s(cumulative, draw()*total)
+		hist[coords[min(i, len(coords)-1)]]++
+	}
+	return hist
+}
+
+// WorldStats — сводка состояния мира, см. World.Statistics.
+type WorldStats struct {
+	Objects   int // всего объектов
+	Collapsed int // из них коллапсированных
+
+	// Энтропии в битах по несколлапсированным объектам; нули, если таких нет.
+	MeanEntropy, MinEntropy, MaxEntropy float64
+	// Имена несколлапсированных объектов с наименьшей и наибольшей энтропией.
+	MostLocalized, LeastLocalized string
+
+	DensestCell     [2]int  // клетка с наибольшей суммой вероятностей всех объектов
+	DensestWeight   float64 // эта сумма
+	CoveredCells    int     // клетки сетки с ненулевой вероятностью хотя бы у одного объекта
+	CoveredFraction float64 // CoveredCells / (Width·Height)
+}
+
+// Statistics собирает сводку мира за один проход: число объектов и коллапсов,
+// энтропии несколлапсированных объектов, самую плотную клетку и долю сетки,
+// покрытую распределениями. Коллапсированные объекты учитываются в плотности
+// и покрытии дельтой в FinalCoord. При равенстве выбирается объект, идущий
+// раньше в Objects, и наименьшая клетка в порядке (x, y).
+func (w *World) Statistics() WorldStats {
+	objs := w.objects()
+	stats := WorldStats{Objects: len(objs)}
+	density := make(map[[2]int]float64)
+	open := 0
+	for _, obj := range objs {
+		for c, p := range obj.probabilities() {
+			if p > 0 {
+				density[c] += p
+			}
+		}
+		if obj.isCollapsed() {
+			stats.Collapsed++
+			continue
+		}
+		h := obj.Entropy()
+		if open == 0 || h < stats.MinEntropy {
+			stats.MinEntropy, stats.MostLocalized = h, obj.Name
+		}
+		if open == 0 || h > stats.MaxEntropy {
+			stats.MaxEntropy, stats.LeastLocalized = h, obj.Name
+		}
+		stats.MeanEntropy += h
+		open++
+	}
+	if open > 0 {
+		stats.MeanEntropy /= float64(open)
+	}
+	for _, c := range sortedCoords(density) {
+		if density[c] > stats.DensestWeight {
+			stats.DensestCell, stats.DensestWeight = c, density[c]
+		}
+		if w.InBounds(c) {
+			stats.CoveredCells++
+		}
+	}
+	if n := w.Width * w.Height; n > 0 {
+		stats.CoveredFraction = float64(stats.CoveredCells) / float64(n)
+	}
+	return stats
+}
+
+func (s WorldStats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "objects: %d (%d collapsed)\n", s.Objects, s.Collapsed)
+	if s.Objects > s.Collapsed {
+		fmt.Fprintf(&b, "entropy: mean %.3f, min %.3f (%s), max %.3f (%s)\n",
+			s.MeanEntropy, s.MinEntropy, s.MostLocalized, s.MaxEntropy, s.LeastLocalized)
+	}
+	fmt.Fprintf(&b, "densest cell: (%d, %d) with %.3f\n", s.DensestCell[0], s.DensestCell[1], s.DensestWeight)
+	fmt.Fprintf(&b, "coverage: %d cells (%.1f%%)", s.CoveredCells, 100*s.CoveredFraction)
+	return b.String()
+}
@@ -0,0 +1,338 @@
+package quantum
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestProbabilityAtDoesNotMutate(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 3, {1, 1}: 1})
+	if p := obj.ProbabilityAt(0, 0); p != 0.75 {
+		t.Errorf("expected 0.75, got %f", p)
+	}
+	if p := obj.ProbabilityAt(2, 2); p != 0 {
+		t.Errorf("absent cell should have probability 0, got %f", p)
+	}
+	if obj.CoordDist[[2]int{0, 0}] != 3 {
+		t.Error("ProbabilityAt must not normalize the live distribution")
+	}
+
+	obj.Collapse()
+	x, y := obj.FinalCoord[0], obj.FinalCoord[1]
+	if obj.ProbabilityAt(x, y) != 1 || obj.ProbabilityAt(x+1, y) != 0 {
+		t.Error("collapsed object should be a delta at FinalCoord")
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	dist := make(map[[2]int]float64)
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			dist[[2]int{x, y}] = 1
+		}
+	}
+	dist[[2]int{20, 20}] = 0
+	obj := NewQuantumObject("U", dist)
+	if h := obj.Entropy(); math.Abs(h-math.Log2(100)) > 1e-9 {
+		t.Errorf("expected log2(100), got %f", h)
+	}
+	if obj.CoordDist[[2]int{0, 0}] != 1 {
+		t.Error("Entropy must not normalize the live distribution")
+	}
+	obj.Collapse()
+	if h := obj.Entropy(); h != 0 {
+		t.Errorf("collapsed object should have zero entropy, got %f", h)
+	}
+}
+
+func TestEntropiesDropAfterInteraction(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1, {2, 2}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1, {3, 3}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+
+	before := world.Entropies()
+	world.MeasureInteraction(a, b)
+	after := world.Entropies()
+	for _, name := range []string{"A", "B"} {
+		if after[name] >= before[name] {
+			t.Errorf("%s: entropy should drop after measurement (%f -> %f)", name, before[name], after[name])
+		}
+	}
+}
+
+func TestMarginals(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {0, 2}: 1, {1, 2}: 2})
+	mx, my := obj.MarginalX(), obj.MarginalY()
+	sx, sy := 0.0, 0.0
+	for _, p := range mx {
+		sx += p
+	}
+	for _, p := range my {
+		sy += p
+	}
+	if math.Abs(sx-1) > 1e-12 || math.Abs(sy-1) > 1e-12 {
+		t.Errorf("marginals should sum to 1: x=%f y=%f", sx, sy)
+	}
+	if mx[0] != 0.5 || mx[1] != 0.5 || my[0] != 0.25 || my[2] != 0.75 {
+		t.Errorf("marginals inconsistent with joint: x=%v y=%v", mx, my)
+	}
+
+	obj.Collapse()
+	if obj.MarginalX()[obj.FinalCoord[0]] != 1 || obj.MarginalY()[obj.FinalCoord[1]] != 1 {
+		t.Error("collapsed marginals should be deltas at FinalCoord")
+	}
+}
+
+func TestIsFactorized(t *testing.T) {
+	uniform := NewDenseDistribution(4, 3)
+	for i := range uniform.Weights {
+		uniform.Weights[i] = 1
+	}
+	if !NewQuantumObjectFrom("U", uniform).IsFactorized(1e-12) {
+		t.Error("uniform grid is a product of its marginals")
+	}
+	if !NewEllipticalGaussian("E", 2, 2, 2, 0.5, 6, 6).IsFactorized(1e-12) {
+		t.Error("axis-aligned Gaussian is a product of its marginals")
+	}
+	if NewQuantumObject("G", gridDist(4, 3)).IsFactorized(1e-3) {
+		t.Error("mixed weights should not factorize")
+	}
+	diagonal := NewQuantumObject("D", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	if diagonal.IsFactorized(1e-3) {
+		t.Error("diagonal distribution correlates x with y")
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 3, {1, 0}: 1, {2, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 6, {1, 0}: 2, {2, 0}: 2})
+	c := NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1, {1, 0}: 3, {2, 0}: 3})
+	if r := Correlation(a, b); math.Abs(r-1) > 1e-9 {
+		t.Errorf("proportional distributions should correlate perfectly, got %f", r)
+	}
+	if r := Correlation(a, c); math.Abs(r+1) > 1e-9 {
+		t.Errorf("mirrored mass should anticorrelate, got %f", r)
+	}
+	if r := Correlation(a, NewQuantumObject("U", map[[2]int]float64{{0, 0}: 1, {1, 0}: 1, {2, 0}: 1})); r != 0 {
+		t.Errorf("degenerate variance should give 0, got %f", r)
+	}
+}
+
+func TestMostLikely(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{3, 1}: 2, {1, 4}: 2, {1, 2}: 2, {0, 0}: 1})
+	c, p := obj.MostLikely()
+	if c != [2]int{1, 2} || math.Abs(p-2.0/7) > 1e-12 {
+		t.Errorf("ties should resolve to the smallest coordinate, got %v %f", c, p)
+	}
+
+	obj.Collapse()
+	if c, p := obj.MostLikely(); c != obj.FinalCoord || p != 1 {
+		t.Errorf("collapsed mode should be FinalCoord with 1, got %v %f", c, p)
+	}
+}
+
+func TestHellingerDistance(t *testing.T) {
+	p := NewQuantumObject("P", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	q := NewQuantumObject("Q", map[[2]int]float64{{0, 0}: 3, {1, 1}: 1, {2, 2}: 0})
+	if d := HellingerDistance(p, p); d != 0 {
+		t.Errorf("distance to itself = %v, want 0", d)
+	}
+	want := math.Sqrt((math.Pow(math.Sqrt(0.5)-math.Sqrt(0.75), 2) + math.Pow(math.Sqrt(0.5)-math.Sqrt(0.25), 2)) / 2)
+	if d1, d2 := HellingerDistance(p, q), HellingerDistance(q, p); math.Abs(d1-want) > 1e-12 || d1 != d2 {
+		t.Errorf("H(p,q) = %v, H(q,p) = %v, want symmetric %v", d1, d2, want)
+	}
+
+	far := NewQuantumObject("F", map[[2]int]float64{{5, 5}: 1})
+	if d := HellingerDistance(p, far); math.Abs(d-1) > 1e-12 {
+		t.Errorf("disjoint supports should be at distance 1, got %v", d)
+	}
+	partial := NewQuantumObject("R", map[[2]int]float64{{0, 0}: 1, {5, 5}: 1})
+	if d := HellingerDistance(p, partial); d <= 0 || d >= 1 || !math.IsInf(KLDivergence(p, partial), 1) {
+		t.Errorf("partially overlapping supports should give a distance in (0, 1) where KL diverges, got %v", d)
+	}
+
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1, {2, 2}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1, {0, 0}: 1})
+	world.MeasureInteraction(a, b)
+	if d := HellingerDistance(a, b); d != 0 {
+		t.Errorf("objects that met in one cell should have converged, distance %v", d)
+	}
+}
+
+func TestSlicedWassersteinDistance(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{3, 0}: 1})
+	if d := SlicedWassersteinDistance(a, a, 10, rng); d != 0 {
+		t.Errorf("distance to itself = %v, want 0", d)
+	}
+	if d := SlicedWassersteinDistance(a, b, 4000, rng); math.Abs(d-6/math.Pi) > 0.05 {
+		t.Errorf("point masses 3 apart: got %v, want about 6/π", d)
+	}
+
+	cloud := NewQuantumObject("C", GaussianDistribution(20, 20, 5, 5, 1.5))
+	near := NewQuantumObject("N", GaussianDistribution(20, 20, 7, 5, 1.5))
+	far := NewQuantumObject("F", GaussianDistribution(20, 20, 12, 5, 1.5))
+	dNear := SlicedWassersteinDistance(cloud, near, 500, rng)
+	dFar := SlicedWassersteinDistance(cloud, far, 500, rng)
+	if dNear >= dFar {
+		t.Errorf("distance should grow with separation: near %v, far %v", dNear, dFar)
+	}
+	if math.Abs(dFar/dNear-3.5) > 0.2 {
+		t.Errorf("shifted clouds should scale like the shift: ratio %v, want 3.5", dFar/dNear)
+	}
+
+	d1 := SlicedWassersteinDistance(cloud, far, 20, rand.New(rand.NewSource(1)))
+	d2 := SlicedWassersteinDistance(cloud, far, 20, rand.New(rand.NewSource(1)))
+	if math.Abs(d1-d2) > 1e-12 {
+		t.Errorf("same seed should give the same estimate: %v vs %v", d1, d2)
+	}
+}
+
+func TestKLDivergence(t *testing.T) {
+	p := NewQuantumObject("P", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	q := NewQuantumObject("Q", map[[2]int]float64{{0, 0}: 3, {1, 1}: 1})
+	if d := KLDivergence(p, p); d != 0 {
+		t.Errorf("divergence from itself should be 0, got %f", d)
+	}
+	want := 0.5*math.Log2(0.5/0.75) + 0.5*math.Log2(0.5/0.25)
+	if d := KLDivergence(p, q); math.Abs(d-want) > 1e-12 {
+		t.Errorf("expected %f, got %f", want, d)
+	}
+	r := NewQuantumObject("R", map[[2]int]float64{{0, 0}: 1})
+	if d := KLDivergence(p, r); !math.IsInf(d, 1) {
+		t.Errorf("missing support should give +Inf, got %f", d)
+	}
+
+	prior := q.Clone()
+	q.CollapseWith(0.9)
+	if d := KLDivergence(q, prior); math.Abs(d-2) > 1e-12 {
+		t.Errorf("collapse into a cell of probability 1/4 should gain 2 bits, got %f", d)
+	}
+}
+
+func TestExpectedPosition(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {3, 1}: 1})
+	if x, y := obj.ExpectedPosition(); x != 1.5 || y != 0.5 {
+		t.Errorf("expected (1.5, 0.5), got (%f, %f)", x, y)
+	}
+	obj.Collapse()
+	x, y := obj.ExpectedPosition()
+	if x != float64(obj.FinalCoord[0]) || y != float64(obj.FinalCoord[1]) {
+		t.Errorf("collapsed object should report FinalCoord, got (%f, %f)", x, y)
+	}
+}
+
+func TestVariance(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 2}: 1, {4, 2}: 1})
+	if vx, vy := obj.Variance(); vx != 4 || vy != 0 {
+		t.Errorf("expected (4, 0), got (%f, %f)", vx, vy)
+	}
+	if sx, _ := obj.StdDev(); sx != 2 {
+		t.Errorf("expected std 2, got %f", sx)
+	}
+	point := NewQuantumObject("P", map[[2]int]float64{{1000000, 3}: 1})
+	if vx, vy := point.Variance(); vx != 0 || vy != 0 {
+		t.Errorf("single-cell distribution should have zero variance, got (%g, %g)", vx, vy)
+	}
+}
+
+func TestWorldCorrelation(t *testing.T) {
+	world := NewWorld(WithSize(6, 6))
+	a := NewQuantumObject("A", UniformDistribution(6, 6))
+	b := NewQuantumObject("B", UniformDistribution(6, 6))
+	if cx, cy := world.Correlation(a, b); math.Abs(cx-1) > 1e-9 || math.Abs(cy-1) > 1e-9 {
+		t.Errorf("objects sharing cells should correlate through co-location, got %f %f", cx, cy)
+	}
+	left := NewQuantumObject("L", map[[2]int]float64{{0, 0}: 1, {0, 1}: 1})
+	right := NewQuantumObject("R", map[[2]int]float64{{5, 0}: 1, {5, 1}: 1})
+	if cx, cy := world.Correlation(left, right); cx != 0 || cy != 0 {
+		t.Errorf("objects without common cells should be uncorrelated, got %f %f", cx, cy)
+	}
+	column := NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1, {0, 1}: 3, {1, 1}: 1})
+	if cx, cy := world.Correlation(left, column); cx != 0 || math.Abs(cy-1) > 1e-9 {
+		t.Errorf("co-location along a single column should correlate only y, got %f %f", cx, cy)
+	}
+
+	world.Entangle(a, b, func(c [2]int) [2]int { return c })
+	if cx, cy := world.Correlation(a, b); math.Abs(cx-1) > 1e-9 || math.Abs(cy-1) > 1e-9 {
+		t.Errorf("co-located objects should be fully correlated, got %f %f", cx, cy)
+	}
+
+	c := NewQuantumObject("C", UniformDistribution(6, 6))
+	d := NewQuantumObject("D", UniformDistribution(6, 6))
+	world.Entangle(c, d, func(p [2]int) [2]int { return [2]int{5 - p[0], p[1]} })
+	if cx, cy := world.Correlation(d, c); math.Abs(cx+1) > 1e-9 || math.Abs(cy-1) > 1e-9 {
+		t.Errorf("mirrored x should anticorrelate, got %f %f", cx, cy)
+	}
+
+	e := NewQuantumObject("E", UniformDistribution(6, 6))
+	f := NewQuantumObject("F", UniformDistribution(6, 6))
+	pair := world.EntangleJoint(e, f, nil)
+	if cx, cy := pair.Correlation(); math.Abs(cx) > 1e-9 || math.Abs(cy) > 1e-9 {
+		t.Errorf("unmodulated joint should be uncorrelated, got %f %f", cx, cy)
+	}
+}
+
+func TestSample(t *testing.T) {
+	dist := map[[2]int]float64{{0, 0}: 1, {1, 0}: 2, {2, 3}: 3, {4, 4}: 4}
+	obj := NewQuantumObject("A", dist)
+	const n = 100000
+	hist := obj.Sample(n, rand.New(rand.NewSource(1)))
+	if obj.IsCollapsed {
+		t.Fatal("sampling should leave the object uncollapsed")
+	}
+
+	// χ² с тремя степенями свободы: 16.27 — квантиль 0.999.
+	chi2, sum := 0.0, 0
+	for c, w := range dist {
+		expected := n * w / 10
+		d := float64(hist[c]) - expected
+		chi2 += d * d / expected
+		sum += hist[c]
+	}
+	if sum != n || len(hist) != len(dist) {
+		t.Errorf("histogram should cover only the support with %d draws, got %v", n, hist)
+	}
+	if chi2 > 16.27 {
+		t.Errorf("histogram deviates from the distribution: χ² = %f, %v", chi2, hist)
+	}
+
+	obj.CollapseWith(0)
+	if h := obj.Sample(5, nil); h[[2]int{0, 0}] != 5 || len(h) != 1 {
+		t.Errorf("collapsed object should always sample FinalCoord, got %v", h)
+	}
+}
+
+func TestWorldStatistics(t *testing.T) {
+	world := NewWorld(WithSize(4, 4))
+	wide := NewQuantumObject("wide", UniformDistribution(4, 2))
+	narrow := NewQuantumObject("narrow", map[[2]int]float64{{1, 1}: 1, {2, 1}: 1})
+	done := NewQuantumObject("done", map[[2]int]float64{{1, 1}: 1})
+	world.AddQuantumObject(wide)
+	world.AddQuantumObject(narrow)
+	world.AddQuantumObject(done)
+	done.Collapse()
+
+	s := world.Statistics()
+	if s.Objects != 3 || s.Collapsed != 1 {
+		t.Errorf("counts = %d/%d, want 3/1", s.Objects, s.Collapsed)
+	}
+	if s.MostLocalized != "narrow" || s.LeastLocalized != "wide" || s.MinEntropy != 1 || s.MaxEntropy != 3 || s.MeanEntropy != 2 {
+		t.Errorf("unexpected entropy summary: %+v", s)
+	}
+	if s.DensestCell != [2]int{1, 1} || math.Abs(s.DensestWeight-1.625) > 1e-12 {
+		t.Errorf("densest cell = %v (%f), want (1,1) with 1.625", s.DensestCell, s.DensestWeight)
+	}
+	if s.CoveredCells != 8 || s.CoveredFraction != 0.5 {
+		t.Errorf("coverage = %d (%f), want 8 cells, 0.5", s.CoveredCells, s.CoveredFraction)
+	}
+	if str := s.String(); !strings.Contains(str, "3 (1 collapsed)") || !strings.Contains(str, "50.0%") {
+		t.Errorf("unexpected summary:\n%s", str)
+	}
+}
@@ -0,0 +1,187 @@
+package quantum
+
+import (
+	"math"
+	"math/rand"
+)
+
+// CollapseStrategy выбирает клетку коллапса. dist — нормированная копия
+// распределения объекта без нулевых клеток (непустая); rng — генератор
+// объекта или мира, либо nil, и тогда используется глобальный генератор
+// math/rand. Стратегия должна вернуть клетку из dist.
+type CollapseStrategy interface {
+	Pick(dist map[[2]int]float64, rng *rand.Rand) [2]int
+}
+
+// RandomWeightedStrategy — поведение по умолчанию: случайная клетка
+// с вероятностью из распределения, выбранная обратной функцией
+// распределения в порядке sortedCoords.
+type RandomWeightedStrategy struct{}
+
+func (RandomWeightedStrategy) Pick(dist map[[2]int]float64, rng *rand.Rand) [2]int {
+	c, _ := inverseCDF(dist, randFloat(rng))
+	return c
+}
+
+// ArgmaxStrategy детерминированно выбирает наиболее вероятную клетку;
+// при равенстве — первую в порядке sortedCoords.
+type ArgmaxStrategy struct{}
+
+func (ArgmaxStrategy) Pick(dist map[[2]int]float64, _ *rand.Rand) [2]int {
+	return argmax(dist)
+}
+
+// SoftmaxStrategy выбирает клетку с весом p^(1/Temperature) — распределение
+// Больцмана по логарифмам вероятностей. Temperature = 1 совпадает с
+// RandomWeightedStrategy, малая температура приближается к ArgmaxStrategy,
+// большая — к равномерному выбору по носителю. Неположительная температура
+// равносильна ArgmaxStrategy.
+type SoftmaxStrategy struct {
+	Temperature float64
+}
+
+func (s SoftmaxStrategy) Pick(dist map[[2]int]float64, rng *rand.Rand) [2]int {
+	if s.Temperature <= 0 {
+		return argmax(dist)
+	}
+	best := dist[argmax(dist)]
+	tempered := make(map[[2]int]float64, len(dist))
+	total := 0.0
+	for c, p := range dist {
+		w := math.Exp((math.Log(p) - math.Log(best)) / s.Temperature)
+		tempered[c] = w
+		total += w
+	}
+	for c, w := range tempered {
+		tempered[c] = w / total
+	}
+	c, _ := inverseCDF(tempered, randFloat(rng))
+	return c
+}
+
+// ThompsonSamplingStrategy рассматривает распределение как оценку с
+// неопределённостью: вероятности клеток выбираются из распределения Дирихле
+// с параметрами Concentration·p, и коллапс происходит в клетку с наибольшей
+// выбранной вероятностью. Большая Concentration приближает стратегию
+// к ArgmaxStrategy; неположительная считается равной 1.
+type ThompsonSamplingStrategy struct {
+	Concentration float64
+}
+
+func (s ThompsonSamplingStrategy) Pick(dist map[[2]int]float64, rng *rand.Rand) [2]int {
+	alpha := s.Concentration
+	if alpha <= 0 {
+		alpha = 1
+	}
+	var best [2]int
+	bestDraw := math.Inf(-1)
+	for _, c := range sortedCoords(dist) {
+		// Для argmax нормировка выборки Дирихле не нужна: достаточно гамма-величин.
+		if g := gammaDraw(alpha*dist[c], rng); g > bestDraw {
+			best, bestDraw = c, g
+		}
+	}
+	return best
+}
+
+// SetCollapseStrategy задаёт стратегию коллапса объекта; nil возвращает
+// RandomWeightedStrategy. Стратегия действует в Collapse, CollapseAll
+// и измерениях; CollapseWith всегда выбирает клетку по заданному r.
+func (q *QuantumObject) SetCollapseStrategy(s CollapseStrategy) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.strategy = s
+}
+
+// NewQuantumObjectWithStrategy создаёт объект с заданной стратегией коллапса.
+func NewQuantumObjectWithStrategy(name string, dist map[[2]int]float64, s CollapseStrategy) *QuantumObject {
+	return NewQuantumObject(name, dist, WithCollapseStrategy(s))
+}
+
+// pickLocked выполняет коллапс стратегией объекта при захваченной блокировке.
+func (q *QuantumObject) pickLocked(fallback *lockedRand) bool {
+	q.normalizeLocked()
+	dist := make(map[[2]int]float64, len(q.CoordDist))
+	for c, p := range q.CoordDist {
+		if p > 0 {
+			dist[c] = p
+		}
+	}
+	if len(dist) == 0 {
+		return false
+	}
+	var coord [2]int
+	withRand(q.rng, fallback, func(r *rand.Rand) { coord = q.strategy.Pick(dist, r) })
+	q.settleLocked(coord)
+	return true
+}
+
+// inverseCDF выбирает клетку нормированного распределения по значению r
+// в порядке sortedCoords. Если из-за округления накопленная сумма не достигла r,
+// выбирается последняя клетка с ненулевой вероятностью.
+func inverseCDF(dist map[[2]int]float64, r float64) ([2]int, bool) {
+	cumulative := 0.0
+	var last [2]int
+	found := false
+	for _, coord := range sortedCoords(dist) {
+		prob := dist[coord]
+		if prob <= 0 {
+			continue
+		}
+		cumulative += prob
+		last, found = coord, true
+		if r <= cumulative {
+			return coord, true
+		}
+	}
+	return last, found
+}
+
+// argmax — клетка с наибольшим весом, при равенстве первая в порядке sortedCoords.
+func argmax(dist map[[2]int]float64) [2]int {
+	var best [2]int
+	bestP := math.Inf(-1)
+	for _, c := range sortedCoords(dist) {
+		if p := dist[c]; p > bestP {
+			best, bestP = c, p
+		}
+	}
+	return best
+}
+
+func randFloat(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.Float64()
+	}
+	return rng.Float64()
+}
+
+func randNorm(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.NormFloat64()
+	}
+	return rng.NormFloat64()
+}
+
+// gammaDraw выбирает величину из гамма-распределения с формой shape > 0
+// и единичным масштабом (метод Марсальи–Цана).
+func gammaDraw(shape float64, rng *rand.Rand) float64 {
+	if shape < 1 {
+		// Γ(a) = Γ(a+1)·U^(1/a)
+		return gammaDraw(shape+1, rng) * math.Pow(randFloat(rng), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := randNorm(rng)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := randFloat(rng)
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package quantum
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCollapseStrategies(t *testing.T) {
+	dist := func() map[[2]int]float64 {
+		return map[[2]int]float64{{0, 0}: 0.2, {1, 0}: 0.5, {2, 0}: 0.3}
+	}
+	counts := func(s CollapseStrategy) map[[2]int]int {
+		hist := make(map[[2]int]int)
+		rng := rand.New(rand.NewSource(3))
+		for range 4000 {
+			obj := NewQuantumObjectWithStrategy("A", dist(), s)
+			obj.SetRand(rng)
+			obj.Collapse()
+			hist[obj.FinalCoord]++
+		}
+		return hist
+	}
+
+	if h := counts(ArgmaxStrategy{}); h[[2]int{1, 0}] != 4000 {
+		t.Errorf("argmax should always pick the mode, got %v", h)
+	}
+	weighted := counts(RandomWeightedStrategy{})
+	if n := weighted[[2]int{1, 0}]; n < 1850 || n > 2150 {
+		t.Errorf("weighted strategy should follow the distribution, got %v", weighted)
+	}
+	if h := counts(SoftmaxStrategy{Temperature: 0.05}); h[[2]int{1, 0}] < 3950 {
+		t.Errorf("cold softmax should approach argmax, got %v", h)
+	}
+	hot := counts(SoftmaxStrategy{Temperature: 100})
+	if n := hot[[2]int{0, 0}]; n < 1200 || n > 1450 {
+		t.Errorf("hot softmax should approach uniform choice, got %v", hot)
+	}
+	thompson := counts(ThompsonSamplingStrategy{Concentration: 10})
+	if len(thompson) != 3 || thompson[[2]int{1, 0}] < thompson[[2]int{2, 0}] {
+		t.Errorf("Thompson sampling should explore but favour the mode, got %v", thompson)
+	}
+	if h := counts(ThompsonSamplingStrategy{Concentration: 1e4}); h[[2]int{1, 0}] < 3990 {
+		t.Errorf("confident Thompson sampling should approach argmax, got %v", h)
+	}
+}
+
+func TestCollapseStrategyInMeasurement(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 9, {2, 2}: 5})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 9, {2, 2}: 1})
+	a.SetCollapseStrategy(ArgmaxStrategy{})
+	b.SetCollapseStrategy(ArgmaxStrategy{})
+	world.MeasureInteraction(a, b)
+	if a.FinalCoord != [2]int{0, 0} || b.FinalCoord != [2]int{0, 0} {
+		t.Errorf("strategy should pick from the joint distribution: %v %v", a.FinalCoord, b.FinalCoord)
+	}
+
+	c := NewQuantumObjectWithStrategy("C", map[[2]int]float64{{0, 0}: 1, {1, 1}: 9}, ArgmaxStrategy{})
+	c.SetCollapseStrategy(nil)
+	c.CollapseWith(0)
+	if c.FinalCoord != [2]int{0, 0} {
+		t.Errorf("CollapseWith should ignore the strategy, got %v", c.FinalCoord)
+	}
+	if clone := NewQuantumObjectWithStrategy("D", nil, ArgmaxStrategy{}).Clone(); clone.strategy == nil {
+		t.Error("Clone should keep the collapse strategy")
+	}
+}
@@ -0,0 +1,158 @@
+package quantum
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// colormaps — опорные цвета палитр ExportSVG от нулевой до максимальной вероятности.
+var colormaps = map[string][][3]float64{
+	"hot":     {{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {1, 1, 1}},
+	"cool":    {{0, 1, 1}, {1, 0, 1}},
+	"viridis": {{0.267, 0.005, 0.329}, {0.229, 0.322, 0.546}, {0.128, 0.567, 0.551}, {0.369, 0.789, 0.383}, {0.993, 0.906, 0.144}},
+}
+
+// highlightColor — цвет FinalCoord коллапсированного объекта.
+const highlightColor = "#00ff66"
+
+// overlayPalette — цвета слоёв ExportSVGOverlay по порядку объектов.
+var overlayPalette = []string{"#e41a1c", "#377eb8", "#4daf4a", "#984ea3", "#ff7f00", "#a65628", "#f781bf", "#999999"}
+
+// legendSteps — число полос в легенде ExportSVG.
+const legendSteps = 32
+
+// ExportSVG записывает распределение в виде SVG-тепловой карты: каждая клетка —
+// прямоугольник cellSize×cellSize, цвет которого задаётся вероятностью
+// относительно максимальной по палитре colormap ("hot", "cool", "viridis").
+// Под картой выводится легенда. Коллапсированный объект показывается одной
+// клеткой FinalCoord цвета highlightColor. Сетка — размер мира объекта,
+// а вне мира — наименьший прямоугольник от (0,0), вмещающий распределение.
+func (q *QuantumObject) ExportSVG(w io.Writer, cellSize int, colormap string) error {
+	stops, ok := colormaps[colormap]
+	if !ok {
+		return fmt.Errorf("unknown colormap %q", colormap)
+	}
+	if cellSize <= 0 {
+		return fmt.Errorf("cell size must be positive, got %d", cellSize)
+	}
+	probs := q.probabilities()
+	width, height := q.gridSize(probs)
+	q.mu.RLock()
+	collapsed, final := q.IsCollapsed, q.FinalCoord
+	q.mu.RUnlock()
+
+	peak := 0.0
+	for _, p := range probs {
+		peak = max(peak, p)
+	}
+	legendH := cellSize
+	gap := cellSize / 2
+	var b strings.Builder
+	svgHeader(&b, width*cellSize, height*cellSize+gap+legendH+cellSize)
+	for _, c := range sortedCoords(probs) {
+		if c[0] < 0 || c[0] >= width || c[1] < 0 || c[1] >= height {
+			continue
+		}
+		fill := colorAt(stops, probs[c]/peak)
+		if collapsed && c == final {
+			fill = highlightColor
+		}
+		svgRect(&b, c[0]*cellSize, c[1]*cellSize, cellSize, cellSize, fill, 1)
+	}
+
+	top := height*cellSize + gap
+	step := float64(width*cellSize) / legendSteps
+	for i := 0; i < legendSteps; i++ {
+		x := int(math.Round(float64(i) * step))
+		next := int(math.Round(float64(i+1) * step))
+		svgRect(&b, x, top, next-x, legendH, colorAt(stops, (float64(i)+0.5)/legendSteps), 1)
+	}
+	fmt.Fprintf(&b, `<text x="0" y="%d" font-size="%d">0</text>`+"\n", top+legendH+cellSize, cellSize*3/4)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="%d" text-anchor="end">%.4g</text>`+"\n",
+		width*cellSize, top+legendH+cellSize, cellSize*3/4, peak)
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportSVGOverlay записывает распределения всех объектов мира на одной сетке
+// Width×Height: каждый объект — отдельный слой <g> своего цвета, прозрачность
+// клетки пропорциональна её вероятности относительно максимума объекта.
+func (w *World) ExportSVGOverlay(out io.Writer, cellSize int) error {
+	if cellSize <= 0 {
+		return fmt.Errorf("cell size must be positive, got %d", cellSize)
+	}
+	var b strings.Builder
+	svgHeader(&b, w.Width*cellSize, w.Height*cellSize)
+	for i, obj := range w.objects() {
+		probs := obj.probabilities()
+		peak := 0.0
+		for _, p := range probs {
+			peak = max(peak, p)
+		}
+		fmt.Fprintf(&b, `<g fill="%s"><title>%s</title>`+"\n", overlayPalette[i%len(overlayPalette)], svgEscape(obj.Name))
+		for _, c := range sortedCoords(probs) {
+			if c[0] < 0 || c[0] >= w.Width || c[1] < 0 || c[1] >= w.Height {
+				continue
+			}
+			svgRect(&b, c[0]*cellSize, c[1]*cellSize, cellSize, cellSize, "", 0.7*probs[c]/peak)
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// gridSize возвращает размер сетки для ExportSVG.
+func (q *QuantumObject) gridSize(probs map[[2]int]float64) (int, int) {
+	q.mu.RLock()
+	w := q.world
+	q.mu.RUnlock()
+	if w != nil && w.Width > 0 && w.Height > 0 {
+		return w.Width, w.Height
+	}
+	width, height := 1, 1
+	for c := range probs {
+		width, height = max(width, c[0]+1), max(height, c[1]+1)
+	}
+	return width, height
+}
+
+// colorAt интерполирует палитру в точке t ∈ [0,1] и возвращает цвет #rrggbb.
+func colorAt(stops [][3]float64, t float64) string {
+	t = min(max(t, 0), 1)
+	pos := t * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	f := pos - float64(i)
+	var rgb [3]int
+	for k := range rgb {
+		rgb[k] = int(math.Round(255 * (stops[i][k]*(1-f) + stops[i+1][k]*f)))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+func svgHeader(b *strings.Builder, width, height int) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+}
+
+// svgRect пишет прямоугольник; пустой fill наследует цвет группы.
+func svgRect(b *strings.Builder, x, y, width, height int, fill string, opacity float64) {
+	fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d"`, x, y, width, height)
+	if fill != "" {
+		fmt.Fprintf(b, ` fill="%s"`, fill)
+	}
+	if opacity < 1 {
+		fmt.Fprintf(b, ` fill-opacity="%.3f"`, opacity)
+	}
+	b.WriteString("/>\n")
+}
+
+var svgEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func svgEscape(s string) string {
+	return svgEscaper.Replace(s)
+}
@@ -0,0 +1,76 @@
+package quantum
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// svgRects разбирает SVG как XML и возвращает атрибуты всех прямоугольников.
+func svgRects(t *testing.T, data string) []map[string]string {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(data))
+	var rects []map[string]string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return rects
+			}
+			t.Fatalf("invalid SVG: %v\n%s", err, data)
+		}
+		if el, ok := tok.(xml.StartElement); ok && el.Name.Local == "rect" {
+			attrs := make(map[string]string)
+			for _, a := range el.Attr {
+				attrs[a.Name.Local] = a.Value
+			}
+			rects = append(rects, attrs)
+		}
+	}
+}
+
+func TestExportSVG(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {2, 1}: 3})
+	var buf strings.Builder
+	if err := obj.ExportSVG(&buf, 10, "hot"); err != nil {
+		t.Fatal(err)
+	}
+	rects := svgRects(t, buf.String())
+	if len(rects) != 2+legendSteps {
+		t.Fatalf("expected 2 cells and a legend, got %d rects", len(rects))
+	}
+	if rects[1]["x"] != "20" || rects[1]["fill"] != "#ffffff" {
+		t.Errorf("peak cell should use the top of the colormap, got %v", rects[1])
+	}
+
+	obj.Collapse()
+	buf.Reset()
+	if err := obj.ExportSVG(&buf, 10, "viridis"); err != nil {
+		t.Fatal(err)
+	}
+	if rects := svgRects(t, buf.String()); rects[0]["fill"] != highlightColor {
+		t.Errorf("collapsed cell should be highlighted, got %v", rects[0])
+	}
+
+	if err := obj.ExportSVG(&buf, 10, "jet"); err == nil {
+		t.Error("unknown colormap should be rejected")
+	}
+}
+
+func TestExportSVGOverlay(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	world.AddQuantumObject(NewQuantumObject("A<1>", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1}))
+	world.AddQuantumObject(NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1, {5, 5}: 1}))
+	var buf strings.Builder
+	if err := world.ExportSVGOverlay(&buf, 8); err != nil {
+		t.Fatal(err)
+	}
+	rects := svgRects(t, buf.String())
+	if len(rects) != 3 {
+		t.Errorf("cells outside the world should be skipped, got %d rects", len(rects))
+	}
+	if strings.Count(buf.String(), "<g ") != 2 {
+		t.Error("each object should get its own layer")
+	}
+}
@@ -0,0 +1,169 @@
+package quantum
+
+import (
+	"cmp"
+	"context"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// lockedRand — генератор, защищённый мьютексом: *rand.Rand не потокобезопасен,
+// а генератор мира используется одновременно несколькими измерениями.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(r *rand.Rand) *lockedRand {
+	if r == nil {
+		return nil
+	}
+	return &lockedRand{r: r}
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+// fork возвращает независимый генератор, засеянный очередным значением l,
+// так что последовательность копии детерминирована, но не пересекается с l.
+// Для nil возвращает nil.
+func (l *lockedRand) fork() *lockedRand {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return newLockedRand(rand.New(rand.NewSource(l.r.Int63())))
+}
+
+// drawFloat возвращает случайное число из [0,1) из первого доступного источника:
+// собственного генератора объекта, генератора мира или глобального генератора
+// math/rand, который засевается случайно при старте программы.
+func drawFloat(own, fallback *lockedRand) float64 {
+	switch {
+	case own != nil:
+		return own.Float64()
+	case fallback != nil:
+		return fallback.Float64()
+	}
+	return rand.Float64()
+}
+
+// withRand вызывает fn с генератором own, а при его отсутствии — fallback,
+// удерживая его блокировку; если нет обоих, fn получает nil.
+func withRand(own, fallback *lockedRand, fn func(*rand.Rand)) {
+	l := cmp.Or(own, fallback)
+	if l == nil {
+		fn(nil)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fn(l.r)
+}
+
+// lockPair захватывает блокировки двух объектов в порядке их адресов, чтобы
+// встречные измерения (a, b) и (b, a) не взаимоблокировались.
+func lockPair(a, b *QuantumObject) {
+	if a == b {
+		a.mu.Lock()
+		return
+	}
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.mu.Lock()
+	b.mu.Lock()
+}
+
+func unlockPair(a, b *QuantumObject) {
+	a.mu.Unlock()
+	if a != b {
+		b.mu.Unlock()
+	}
+}
+
+// objects возвращает снимок Objects под блокировкой мира. Операции над
+// объектами выполняются по снимку без удержания блокировки, так что обработчики
+// коллапса могут добавлять объекты в мир.
+func (w *World) objects() []*QuantumObject {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.Objects)
+}
+
+// collapseHooks возвращает снимок обработчиков коллапса мира.
+func (w *World) collapseHooks() []func(*QuantumObject) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.hooks)
+}
+
+// MeasureInteractionAsync запускает MeasureInteraction в отдельной горутине и
+// возвращает канал, закрываемый по завершении измерения.
+func (w *World) MeasureInteractionAsync(obj1, obj2 *QuantumObject) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.MeasureInteraction(obj1, obj2)
+	}()
+	return done
+}
+
+// lockAll захватывает блокировки объектов в порядке адресов (повторы
+// пропускаются) и возвращает захваченные объекты для unlockAll.
+func lockAll(objs []*QuantumObject) []*QuantumObject {
+	locked := slices.Clone(objs)
+	slices.SortFunc(locked, func(a, b *QuantumObject) int {
+		return cmp.Compare(uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(b)))
+	})
+	locked = slices.Compact(locked)
+	for _, obj := range locked {
+		obj.mu.Lock()
+	}
+	return locked
+}
+
+func unlockAll(locked []*QuantumObject) {
+	for _, obj := range locked {
+		obj.mu.Unlock()
+	}
+}
+
+// CollapseAllParallel коллапсирует все объекты мира, распределяя их поровну
+// между workers горутинами (workers <= 0 — по числу GOMAXPROCS). Отмена ctx
+// прекращает коллапс ещё не обработанных объектов; тогда возвращается ctx.Err().
+// Обработчики коллапса мира и объектов вызываются из рабочих горутин и должны
+// допускать параллельный вызов; регистрировать новые во время работы нельзя.
+// Общий генератор мира сериализует выборки, поэтому для масштабирования
+// объектам лучше задать собственные генераторы.
+func (w *World) CollapseAllParallel(ctx context.Context, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	objs := w.objects()
+	defer w.track("CollapseAllParallel", objs...)()
+	chunk := (len(objs) + workers - 1) / max(workers, 1)
+	var wg sync.WaitGroup
+	for start := 0; start < len(objs); start += chunk {
+		part := objs[start:min(start+chunk, len(objs))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, obj := range part {
+				if ctx.Err() != nil {
+					return
+				}
+				obj.collapseFrom(w.rng)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
@@ -0,0 +1,139 @@
+package quantum
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// Запускать с -race: параллельные измерения не должны гоняться за распределениями.
+func TestConcurrentMeasureInteraction(t *testing.T) {
+	world := NewWorldWithRand(10, 10, rand.New(rand.NewSource(1)))
+	var pairs [][2]*QuantumObject
+	for i := 0; i < 20; i++ {
+		dist := map[[2]int]float64{{i % 10, 0}: 1, {i % 10, 1}: 1}
+		a := NewQuantumObject("A", dist)
+		b := NewQuantumObject("B", map[[2]int]float64{{i % 10, 0}: 1, {i % 10, 1}: 1})
+		world.AddQuantumObject(a)
+		world.AddQuantumObject(b)
+		pairs = append(pairs, [2]*QuantumObject{a, b})
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range pairs {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			world.MeasureInteraction(p[0], p[1])
+		}()
+		go func() {
+			defer wg.Done()
+			world.MeasureInteraction(p[1], p[0])
+		}()
+		go func() {
+			defer wg.Done()
+			_ = p[0].ProbabilityAt(0, 0) + p[1].Entropy()
+		}()
+	}
+	wg.Wait()
+
+	for _, p := range pairs {
+		if !p[0].IsCollapsed || !p[1].IsCollapsed || p[0].FinalCoord[0] != p[1].FinalCoord[0] {
+			t.Errorf("pair should collapse within its shared column: %v %v", p[0], p[1])
+		}
+	}
+}
+
+func TestCollapseAllParallel(t *testing.T) {
+	world := NewWorldWithRand(10, 10, rand.New(rand.NewSource(4)))
+	var count sync.Mutex
+	collapsed := 0
+	world.OnCollapse(func(*QuantumObject) {
+		count.Lock()
+		collapsed++
+		count.Unlock()
+	})
+	for i := 0; i < 100; i++ {
+		world.AddQuantumObject(NewQuantumObject("A", gridDist(10, 10)))
+	}
+	if err := world.CollapseAllParallel(context.Background(), 4); err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range world.Objects {
+		if !obj.IsCollapsed {
+			t.Fatalf("%v was not collapsed", obj)
+		}
+	}
+	if collapsed != 100 {
+		t.Errorf("expected 100 callbacks, got %d", collapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	world.ResetAll()
+	if err := world.CollapseAllParallel(ctx, 4); err != context.Canceled {
+		t.Errorf("cancelled context should be reported, got %v", err)
+	}
+	if world.Objects[0].IsCollapsed {
+		t.Error("cancelled run should not collapse objects")
+	}
+}
+
+func benchmarkCollapseAllParallel(b *testing.B, workers int) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		world := NewWorld(WithSize(30, 30))
+		for j := 0; j < 1000; j++ {
+			world.AddQuantumObject(NewQuantumObjectWithRand("A", gridDist(30, 30), rand.New(rand.NewSource(int64(j)))))
+		}
+		b.StartTimer()
+		world.CollapseAllParallel(context.Background(), workers)
+	}
+}
+
+func BenchmarkCollapseAllParallel1(b *testing.B) { benchmarkCollapseAllParallel(b, 1) }
+func BenchmarkCollapseAllParallel4(b *testing.B) { benchmarkCollapseAllParallel(b, 4) }
+
+// Запускать с -race: изменение мира и измерения в разных горутинах.
+func TestConcurrentWorldAccess(t *testing.T) {
+	world := NewWorldWithRand(10, 10, rand.New(rand.NewSource(6)))
+	world.BuildSpatialIndex(2)
+	world.OnCollapse(func(obj *QuantumObject) {
+		if obj.Name == "A" {
+			world.AddQuantumObject(NewQuantumObject("echo", gridDist(2, 2)))
+		}
+	})
+
+	var wg sync.WaitGroup
+	var done []<-chan struct{}
+	for i := 0; i < 10; i++ {
+		a := NewQuantumObject("A", map[[2]int]float64{{i, 0}: 1, {i, 1}: 1})
+		b := NewQuantumObject("B", map[[2]int]float64{{i, 0}: 1, {i, 1}: 1})
+		world.AddQuantumObject(a)
+		world.AddQuantumObject(b)
+		done = append(done, world.MeasureInteractionAsync(a, b))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			world.AddQuantumObject(NewQuantumObject("C", gridDist(3, 3)))
+			world.FindAll("C")
+		}()
+		go func() {
+			defer wg.Done()
+			world.ObjectsNear([2]int{i, 0}, 1)
+			world.Entropies()
+		}()
+	}
+	for _, ch := range done {
+		<-ch
+	}
+	wg.Wait()
+
+	if n := len(world.FindAll("echo")); n != 10 {
+		t.Errorf("each measured A should trigger one callback, got %d", n)
+	}
+	if n := len(world.FindAll("C")); n != 10 {
+		t.Errorf("concurrent additions should all land, got %d", n)
+	}
+}
@@ -0,0 +1,173 @@
+package quantum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LoadWorldFromReader читает мир в текстовом формате. Пустые строки
+// и строки, начинающиеся с '#', пропускаются. Первая значимая строка —
+// заголовок "world W H". Далее идут строки объектов:
+//
+//	john 1,2:0.5 3,4:0.5 5,5
+//
+// — имя и пары x,y:вес (вес по умолчанию 1), и не более одного блока карты:
+//
+//	map 1.5
+//	..J.
+//	....
+//	T..J
+//
+// После "map [sigma]" следуют ровно H строк по W символов. Каждая буква
+// задаёт объект с этим именем: гауссиана с отклонением sigma (по умолчанию 1)
+// в каждой клетке, где буква встречается, с равными весами; '.' — пустая клетка.
+// Объекты добавляются в порядке появления. Ошибки содержат номер строки.
+func LoadWorldFromReader(r io.Reader) (*World, error) {
+	sc := bufio.NewScanner(r)
+	var world *World
+	names := make(map[string]bool)
+	add := func(line int, obj *QuantumObject) error {
+		if names[obj.Name] {
+			return fmt.Errorf("textmap: line %d: duplicate object %q", line, obj.Name)
+		}
+		names[obj.Name] = true
+		world.AddQuantumObject(obj)
+		return nil
+	}
+	line, seenMap := 0, false
+	for sc.Scan() {
+		line++
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch {
+		case world == nil:
+			w, err := parseWorldHeader(fields)
+			if err != nil {
+				return nil, fmt.Errorf("textmap: line %d: %w", line, err)
+			}
+			world = w
+		case fields[0] == "map":
+			if seenMap {
+				return nil, fmt.Errorf("textmap: line %d: more than one map block", line)
+			}
+			seenMap = true
+			objs, read, err := parseMapBlock(sc, fields[1:], world.Width, world.Height, line)
+			line += read
+			if err != nil {
+				return nil, err
+			}
+			for _, obj := range objs {
+				if err := add(line, obj); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			dist, err := parseCells(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("textmap: line %d: object %q: %w", line, fields[0], err)
+			}
+			if err := add(line, NewQuantumObject(fields[0], dist)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if world == nil {
+		return nil, fmt.Errorf("textmap: missing \"world W H\" header")
+	}
+	return world, nil
+}
+
+func parseWorldHeader(fields []string) (*World, error) {
+	if len(fields) != 3 || fields[0] != "world" {
+		return nil, fmt.Errorf("expected \"world W H\" header, got %q", strings.Join(fields, " "))
+	}
+	width, errW := strconv.Atoi(fields[1])
+	height, errH := strconv.Atoi(fields[2])
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid world size %s x %s", fields[1], fields[2])
+	}
+	return NewWorld(WithSize(width, height)), nil
+}
+
+// parseCells разбирает пары "x,y" или "x,y:вес".
+func parseCells(fields []string) (map[[2]int]float64, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no cells")
+	}
+	dist := make(map[[2]int]float64, len(fields))
+	for _, f := range fields {
+		coord, weight, hasWeight := strings.Cut(f, ":")
+		c, err := parseCoordKey(coord)
+		if err != nil {
+			return nil, err
+		}
+		w := 1.0
+		if hasWeight {
+			if w, err = strconv.ParseFloat(weight, 64); err != nil || w < 0 {
+				return nil, fmt.Errorf("invalid weight %q in %q", weight, f)
+			}
+		}
+		dist[c] += w
+	}
+	return dist, nil
+}
+
+// parseMapBlock читает height строк карты после заголовка блока в строке start
+// и возвращает объекты по буквам и число прочитанных строк.
+func parseMapBlock(sc *bufio.Scanner, args []string, width, height, start int) ([]*QuantumObject, int, error) {
+	sigma := 1.0
+	if len(args) > 1 {
+		return nil, 0, fmt.Errorf("textmap: line %d: expected \"map [sigma]\"", start)
+	}
+	if len(args) == 1 {
+		s, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || s <= 0 {
+			return nil, 0, fmt.Errorf("textmap: line %d: invalid sigma %q", start, args[0])
+		}
+		sigma = s
+	}
+	var order []rune
+	centres := make(map[rune][][2]int)
+	for y := 0; y < height; y++ {
+		if !sc.Scan() {
+			return nil, y, fmt.Errorf("textmap: line %d: map has %d rows, want %d", start+y, y, height)
+		}
+		row := []rune(strings.TrimRight(sc.Text(), " \t\r"))
+		if len(row) != width {
+			return nil, y + 1, fmt.Errorf("textmap: line %d: map row has %d cells, want %d", start+y+1, len(row), width)
+		}
+		for x, ch := range row {
+			switch {
+			case ch == '.':
+			case unicode.IsLetter(ch):
+				if centres[ch] == nil {
+					order = append(order, ch)
+				}
+				centres[ch] = append(centres[ch], [2]int{x, y})
+			default:
+				return nil, y + 1, fmt.Errorf("textmap: line %d: unexpected %q in map", start+y+1, ch)
+			}
+		}
+	}
+	objs := make([]*QuantumObject, 0, len(order))
+	for _, ch := range order {
+		components := make([]MixtureComponent, 0, len(centres[ch]))
+		for _, c := range centres[ch] {
+			components = append(components, MixtureComponent{
+				Distribution: GaussianDistribution(width, height, c[0], c[1], sigma),
+				Weight:       1,
+			})
+		}
+		objs = append(objs, NewMixtureQuantumObject(string(ch), components))
+	}
+	return objs, height, nil
+}
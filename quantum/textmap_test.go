@@ -0,0 +1,71 @@
+package quantum
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const sampleMap = `# scenario
+world 6 3
+
+john 1,2:0.5 3,1:0.5
+rock 4,0
+
+map 0.8
+T.....
+.....T
+..S...
+`
+
+func TestLoadWorldFromReader(t *testing.T) {
+	world, err := LoadWorldFromReader(strings.NewReader(sampleMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world.Width != 6 || world.Height != 3 || len(world.Objects) != 4 {
+		t.Fatalf("unexpected world %dx%d with %d objects", world.Width, world.Height, len(world.Objects))
+	}
+	john, _ := world.Find("john")
+	if john.ProbabilityAt(1, 2) != 0.5 || len(john.CoordDist) != 2 {
+		t.Errorf("john = %v", john.CoordDist)
+	}
+	rock, _ := world.Find("rock")
+	if rock.CoordDist[[2]int{4, 0}] != 1 {
+		t.Errorf("weight should default to 1: %v", rock.CoordDist)
+	}
+	tree, ok := world.Find("T")
+	left, right := 0.0, 0.0
+	for c, p := range tree.CoordDist {
+		if c[0] < 3 {
+			left += p
+		} else {
+			right += p
+		}
+	}
+	if !ok || math.Abs(left-right) > 0.01 || tree.ProbabilityAt(2, 1) >= tree.ProbabilityAt(5, 1) {
+		t.Errorf("repeated letter should place equal Gaussian bumps, halves %f and %f", left, right)
+	}
+	if s, _ := world.Find("S"); s.Name != world.Objects[3].Name {
+		t.Error("map objects should follow their first appearance")
+	}
+}
+
+func TestLoadWorldFromReaderErrors(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"", "missing"},
+		{"size 3 3", "line 1"},
+		{"world 3 3\n\njohn 1;2", "line 3"},
+		{"world 3 3\njohn 1,2:-1", "invalid weight"},
+		{"world 3 3\nmap\n...\n..\n...", "line 4"},
+		{"world 3 2\nmap\n...\n.?.", "line 4"},
+		{"world 3 2\nmap\n...", "map has 1 rows"},
+		{"world 3 1\nA 0,0\nmap\nA..", "duplicate"},
+	}
+	for _, tt := range tests {
+		_, err := LoadWorldFromReader(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want mention of %q", tt.input, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package quantum
+
+import (
+	"math"
+	"slices"
+)
+
+// BoundaryMode задаёт поведение сетки на краях.
+type BoundaryMode int
+
+const (
+	// Bounded — сетка с жёсткими краями (по умолчанию).
+	Bounded BoundaryMode = iota
+	// Toroidal — сетка замкнута в тор: выход за край возвращает на противоположный.
+	Toroidal
+	// Reflecting — края отражают: выход за край зеркально возвращает внутрь сетки.
+	Reflecting
+)
+
+func (m BoundaryMode) String() string {
+	switch m {
+	case Bounded:
+		return "bounded"
+	case Toroidal:
+		return "toroidal"
+	case Reflecting:
+		return "reflecting"
+	}
+	return "unknown"
+}
+
+// Neighborhood задаёт, какие клетки считаются соседними.
+type Neighborhood int
+
+const (
+	// VonNeumann — четыре ортогональных соседа (по умолчанию).
+	VonNeumann Neighborhood = iota
+	// Moore — восемь соседей, включая диагональные.
+	Moore
+)
+
+func (n Neighborhood) String() string {
+	switch n {
+	case VonNeumann:
+		return "von Neumann"
+	case Moore:
+		return "Moore"
+	}
+	return "unknown"
+}
+
+var (
+	vonNeumannOffsets = [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	mooreOffsets      = [][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+)
+
+// offsets возвращает сдвиги к соседям для окрестности n.
+func (n Neighborhood) offsets() [][2]int {
+	if n == Moore {
+		return mooreOffsets
+	}
+	return vonNeumannOffsets
+}
+
+// Neighbors возвращает соседей клетки c по окрестности w.Neighborhood
+// с учётом граничного режима: в Bounded — только клетки внутри сетки,
+// в Toroidal и Reflecting — приведённые внутрь. Совпадающие клетки и сама c
+// (возможные на узкой сетке или при отражении) не повторяются.
+func (w *World) Neighbors(c [2]int) [][2]int {
+	offsets := w.Neighborhood.offsets()
+	out := make([][2]int, 0, len(offsets))
+	for _, d := range offsets {
+		if n, ok := w.Wrap(c, d); ok && n != c && !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// InBounds сообщает, лежит ли координата внутри Width×Height.
+func (w *World) InBounds(c [2]int) bool {
+	return c[0] >= 0 && c[0] < w.Width && c[1] >= 0 && c[1] < w.Height
+}
+
+// Wrap сдвигает координату c на d с учётом граничного режима мира.
+// В режиме Bounded второй результат равен false, если точка вышла за сетку;
+// в режимах Toroidal и Reflecting координата приводится внутрь сетки
+// (по модулю размеров или зеркально) и всегда допустима.
+func (w *World) Wrap(c, d [2]int) ([2]int, bool) {
+	n := [2]int{c[0] + d[0], c[1] + d[1]}
+	if w.Boundary != Bounded && w.Width > 0 && w.Height > 0 {
+		return w.fold(n), true
+	}
+	return n, w.InBounds(n)
+}
+
+// fold приводит координату внутрь сетки согласно режиму Toroidal или Reflecting.
+func (w *World) fold(c [2]int) [2]int {
+	if w.Boundary == Reflecting {
+		return [2]int{mirrorIndex(c[0], w.Width), mirrorIndex(c[1], w.Height)}
+	}
+	return [2]int{mod(c[0], w.Width), mod(c[1], w.Height)}
+}
+
+// Distance возвращает евклидово расстояние между клетками. В режиме Toroidal
+// по каждой оси берётся кратчайший путь с учётом замыкания.
+func (w *World) Distance(c1, c2 [2]int) float64 {
+	dx, dy := w.delta(c1[0], c2[0], w.Width), w.delta(c1[1], c2[1], w.Height)
+	return math.Hypot(float64(dx), float64(dy))
+}
+
+// nearest возвращает образ c2, ближайший к c1: c1 плюс кратчайшее смещение
+// из Distance. Вне режима Toroidal это сама c2.
+func (w *World) nearest(c1, c2 [2]int) [2]int {
+	return [2]int{c1[0] - w.delta(c1[0], c2[0], w.Width), c1[1] - w.delta(c1[1], c2[1], w.Height)}
+}
+
+// delta — разность координат по оси размера size с учётом граничного режима.
+func (w *World) delta(a, b, size int) int {
+	d := a - b
+	if w.Boundary != Toroidal || size <= 0 {
+		return d
+	}
+	d = mod(d, size)
+	if d > size/2 {
+		d -= size
+	}
+	return d
+}
+
+// GaussFactor возвращает гауссов множитель exp(-d²/(2σ²)) для расстояния
+// d = Distance(c, center); в режиме Toroidal используется кратчайшее
+// расстояние по тору, так что пик у края плавно переходит на противоположный.
+func (w *World) GaussFactor(c, center [2]int, sigma float64) float64 {
+	d := w.Distance(c, center)
+	return math.Exp(-d * d / (2 * sigma * sigma))
+}
+
+// wrapDist приводит координаты распределения внутрь сетки (для режимов
+// Toroidal и Reflecting), складывая веса совпавших клеток.
+// В режиме Bounded возвращает dist без изменений.
+func (w *World) wrapDist(dist map[[2]int]float64) map[[2]int]float64 {
+	if w.Boundary == Bounded || w.Width <= 0 || w.Height <= 0 {
+		return dist
+	}
+	out := make(map[[2]int]float64, len(dist))
+	for c, p := range dist {
+		out[w.fold(c)] += p
+	}
+	return out
+}
+
+func mod(a, n int) int {
+	return ((a % n) + n) % n
+}
+
+// mirrorIndex зеркально отражает индекс a от краёв отрезка [0, n).
+func mirrorIndex(a, n int) int {
+	a = mod(a, 2*n)
+	if a >= n {
+		a = 2*n - 1 - a
+	}
+	return a
+}
@@ -0,0 +1,109 @@
+package quantum
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestWrapAndDistance(t *testing.T) {
+	world := NewWorld(WithSize(10, 10))
+	if _, ok := world.Wrap([2]int{9, 9}, [2]int{1, 0}); ok {
+		t.Error("bounded world should reject coordinates past the edge")
+	}
+	if d := world.Distance([2]int{0, 0}, [2]int{9, 9}); math.Abs(d-9*math.Sqrt2) > 1e-9 {
+		t.Errorf("bounded distance should be plain euclidean, got %f", d)
+	}
+
+	world.Boundary = Toroidal
+	c, ok := world.Wrap([2]int{9, 9}, [2]int{1, 2})
+	if !ok || c != [2]int{0, 1} {
+		t.Errorf("expected (0,1), got %v", c)
+	}
+	if d := world.Distance([2]int{0, 0}, [2]int{9, 9}); math.Abs(d-math.Sqrt2) > 1e-9 {
+		t.Errorf("toroidal distance (0,0)-(9,9) should be sqrt(2), got %f", d)
+	}
+}
+
+func TestToroidalInteraction(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	world.Boundary = Toroidal
+	a := NewQuantumObject("A", map[[2]int]float64{{-1, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{4, 5}: 1})
+	world.MeasureInteraction(a, b)
+	if !a.IsCollapsed || !b.IsCollapsed || a.FinalCoord != [2]int{4, 0} {
+		t.Errorf("wrapped coordinates should meet at (4,0): %v %v", a, b)
+	}
+}
+
+func TestReflectingBoundary(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	world.Boundary = Reflecting
+	if c, ok := world.Wrap([2]int{0, 4}, [2]int{-1, 2}); !ok || c != [2]int{0, 3} {
+		t.Errorf("expected mirrored (0,3), got %v", c)
+	}
+
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	world.AddQuantumObject(obj)
+	world.Step(1)
+	for c := range obj.CoordDist {
+		if !world.InBounds(c) {
+			t.Errorf("reflecting diffusion leaked mass to %v", c)
+		}
+	}
+	if math.Abs(obj.total()-1) > 1e-9 {
+		t.Errorf("reflecting diffusion should conserve mass, got %f", obj.total())
+	}
+}
+
+func TestToroidalGaussFactor(t *testing.T) {
+	world := NewWorld(WithSize(10, 10))
+	world.Boundary = Toroidal
+	near := world.GaussFactor([2]int{9, 0}, [2]int{0, 0}, 1)
+	if math.Abs(near-math.Exp(-0.5)) > 1e-12 {
+		t.Errorf("edge cells should be one step apart on the torus, got %f", near)
+	}
+}
+
+func TestNeighborsAtCorner(t *testing.T) {
+	sorted := func(cs [][2]int) [][2]int {
+		slices.SortFunc(cs, func(a, b [2]int) int { return cmp.Or(a[0]-b[0], a[1]-b[1]) })
+		return cs
+	}
+	tests := []struct {
+		boundary BoundaryMode
+		hood     Neighborhood
+		want     [][2]int
+	}{
+		{Bounded, VonNeumann, [][2]int{{0, 1}, {1, 0}}},
+		{Bounded, Moore, [][2]int{{0, 1}, {1, 0}, {1, 1}}},
+		{Toroidal, VonNeumann, [][2]int{{0, 1}, {0, 3}, {1, 0}, {4, 0}}},
+		{Toroidal, Moore, [][2]int{{0, 1}, {0, 3}, {1, 0}, {1, 1}, {1, 3}, {4, 0}, {4, 1}, {4, 3}}},
+	}
+	for _, tt := range tests {
+		world := NewWorld(WithSize(5, 4))
+		world.Boundary, world.Neighborhood = tt.boundary, tt.hood
+		if got := sorted(world.Neighbors([2]int{0, 0})); !slices.Equal(got, tt.want) {
+			t.Errorf("%s/%s: neighbors of (0,0) = %v, want %v", tt.boundary, tt.hood, got, tt.want)
+		}
+	}
+}
+
+func TestDiffuseMoore(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	world.Neighborhood = Moore
+	obj := NewQuantumObject("A", map[[2]int]float64{{2, 2}: 1})
+	world.AddQuantumObject(obj)
+	obj.Diffuse(0.8)
+	if len(obj.CoordDist) != 9 || math.Abs(obj.CoordDist[[2]int{1, 1}]-0.1) > 1e-12 {
+		t.Errorf("Moore diffusion should reach diagonals evenly: %v", obj.CoordDist)
+	}
+
+	corner := NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1})
+	world.AddQuantumObject(corner)
+	corner.Diffuse(0.8)
+	if math.Abs(corner.CoordDist[[2]int{0, 0}]-0.7) > 1e-12 {
+		t.Errorf("shares past a bounded edge should stay in the cell, got %v", corner.CoordDist)
+	}
+}
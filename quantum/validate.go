@@ -0,0 +1,166 @@
+package quantum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ValidationKind — вид нарушения, найденного ValidationReport.
+type ValidationKind int
+
+const (
+	OutOfBounds       ValidationKind = iota // координата вне сетки мира
+	ZeroDistribution                        // у несколлапсированного объекта нет положительных весов
+	NegativeWeight                          // отрицательный вес в распределении
+	InvalidFinalCoord                       // коллапсированный объект с нулевой вероятностью в FinalCoord
+)
+
+func (k ValidationKind) String() string {
+	switch k {
+	case OutOfBounds:
+		return "out of bounds"
+	case ZeroDistribution:
+		return "zero distribution"
+	case NegativeWeight:
+		return "negative weight"
+	case InvalidFinalCoord:
+		return "invalid final coordinate"
+	}
+	return fmt.Sprintf("ValidationKind(%d)", int(k))
+}
+
+// ValidationError — одно нарушение: объект, вид и описание.
+type ValidationError struct {
+	ObjectName string
+	Kind       ValidationKind
+	Detail     string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("object %q: %s", e.ObjectName, e.Detail)
+}
+
+// ValidationReport проверяет объекты мира и возвращает все нарушения
+// в порядке Objects: координаты вне [0,Width)×[0,Height), отрицательные веса,
+// распределения без положительных весов и коллапсированные объекты, у которых
+// FinalCoord имеет нулевую вероятность. Пустой срез означает корректный мир.
+func (w *World) ValidationReport() []ValidationError {
+	var report []ValidationError
+	for _, obj := range w.objects() {
+		report = append(report, w.validateObject(obj)...)
+	}
+	return report
+}
+
+// Validate — ValidationReport в виде одной ошибки: nil для корректного мира,
+// иначе объединение (errors.Join) всех ValidationError, которые можно
+// извлечь через errors.As.
+func (w *World) Validate() error {
+	return joinValidation(w.ValidationReport())
+}
+
+// SetStrictMode включает проверку мира после каждой изменяющей операции,
+// отслеживаемой журналом (измерения, коллапсы, CollapseAll и т. п.):
+// если ValidationReport не пуст, операция завершается паникой с ошибкой Validate.
+// Полезно при отладке, чтобы поймать некорректное состояние там, где оно возникло.
+func (w *World) SetStrictMode(strict bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.strict = strict
+}
+
+// AddQuantumObjectStrict добавляет объект только если он проходит проверки
+// Validate; иначе возвращает ошибку, как Validate, и мир не меняется.
+func (w *World) AddQuantumObjectStrict(obj *QuantumObject) error {
+	if err := joinValidation(w.validateObject(obj)); err != nil {
+		return err
+	}
+	w.AddQuantumObject(obj)
+	return nil
+}
+
+func joinValidation(report []ValidationError) error {
+	errs := make([]error, len(report))
+	for i, e := range report {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
+
+// validateObject возвращает нарушения obj: сначала координаты вне сетки
+// и отрицательные веса в порядке sortedCoords, затем нарушения всего распределения.
+func (w *World) validateObject(obj *QuantumObject) []ValidationError {
+	obj.mu.RLock()
+	defer obj.mu.RUnlock()
+	var report []ValidationError
+	add := func(kind ValidationKind, format string, args ...any) {
+		report = append(report, ValidationError{ObjectName: obj.Name, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+	}
+	positive := false
+	dist := obj.coordsLocked()
+	for _, c := range sortedCoords(dist) {
+		if !w.InBounds(c) {
+			add(OutOfBounds, "coordinate (%d, %d) outside %dx%d world", c[0], c[1], w.Width, w.Height)
+		}
+		switch p := dist[c]; {
+		case p < 0:
+			add(NegativeWeight, "negative weight %g at (%d, %d)", p, c[0], c[1])
+		case p > 0:
+			positive = true
+		}
+	}
+	switch c := obj.FinalCoord; {
+	case obj.IsCollapsed && !(dist[c] > 0):
+		add(InvalidFinalCoord, "collapsed at (%d, %d) with zero probability", c[0], c[1])
+	case !obj.IsCollapsed && !positive:
+		add(ZeroDistribution, "distribution has no positive weight")
+	}
+	return report
+}
+
+// Total возвращает сумму весов распределения; для коллапсированного
+// объекта — 1.
+func (q *QuantumObject) Total() float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.IsCollapsed {
+		return 1
+	}
+	return q.total()
+}
+
+// NormalizationError возвращает отклонение Total от 1
+// (положительное, если сумма больше).
+func (q *QuantumObject) NormalizationError() float64 {
+	return q.Total() - 1
+}
+
+// IsNormalized сообщает, отличается ли сумма весов от 1 не более чем на tol.
+func (q *QuantumObject) IsNormalized(tol float64) bool {
+	return math.Abs(q.NormalizationError()) <= tol
+}
+
+// ValidateNormalization возвращает имена объектов мира, распределения которых
+// не нормированы с точностью tol, в порядке Objects. Помогает заметить
+// накопление ошибок округления в длинных симуляциях.
+func (w *World) ValidateNormalization(tol float64) []string {
+	var names []string
+	for _, obj := range w.objects() {
+		if !obj.IsNormalized(tol) {
+			names = append(names, obj.Name)
+		}
+	}
+	return names
+}
+
+// NormalizeAll нормирует распределения всех несколлапсированных объектов мира.
+func (w *World) NormalizeAll() {
+	for _, obj := range w.objects() {
+		obj.mu.Lock()
+		if !obj.IsCollapsed {
+			obj.normalizeLocked()
+		}
+		obj.mu.Unlock()
+	}
+}
@@ -0,0 +1,144 @@
+package quantum
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	world.AddQuantumObject(NewQuantumObject("ok", gridDist(3, 3)))
+	if err := world.Validate(); err != nil {
+		t.Fatalf("in-bounds world should validate, got %v", err)
+	}
+
+	world.AddQuantumObject(NewQuantumObject("bad", map[[2]int]float64{{1, 1}: 1, {3, 0}: 1, {-1, 2}: 1}))
+	err := world.Validate()
+	if err == nil {
+		t.Fatal("out-of-bounds coordinates should be reported")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `"bad"`) || !strings.Contains(msg, "(3, 0)") || !strings.Contains(msg, "(-1, 2)") {
+		t.Errorf("error should name the object and each coordinate: %v", msg)
+	}
+	if strings.Contains(msg, "(1, 1)") {
+		t.Errorf("in-bounds coordinates should not be listed: %v", msg)
+	}
+}
+
+func TestAddQuantumObjectStrict(t *testing.T) {
+	world := NewWorld(WithSize(2, 2))
+	if err := world.AddQuantumObjectStrict(NewQuantumObject("A", gridDist(2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	if err := world.AddQuantumObjectStrict(NewQuantumObject("B", map[[2]int]float64{{2, 2}: 1})); err == nil {
+		t.Error("out-of-bounds object should be rejected")
+	}
+	if len(world.Objects) != 1 {
+		t.Errorf("rejected object must not be added, got %d objects", len(world.Objects))
+	}
+}
+
+func TestValidateNormalization(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 0.5, {1, 1}: 0.5 + 1e-9})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 2, {2, 2}: 1})
+	c := NewQuantumObject("C", map[[2]int]float64{{1, 0}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.AddQuantumObject(c)
+
+	if e := b.NormalizationError(); e != 2 || b.Total() != 3 {
+		t.Errorf("deviation = %f, total = %f, want 2 and 3", e, b.Total())
+	}
+	collapsed := NewQuantumObject("D", map[[2]int]float64{{0, 0}: 4})
+	collapsed.Collapse()
+	collapsed.CoordDist[[2]int{0, 0}] = 7
+	if collapsed.Total() != 1 || !collapsed.IsNormalized(0) {
+		t.Errorf("collapsed object should report total 1, got %f", collapsed.Total())
+	}
+	if !a.IsNormalized(1e-6) || a.IsNormalized(1e-12) {
+		t.Error("tolerance should decide whether small drift counts")
+	}
+	if got := world.ValidateNormalization(1e-6); len(got) != 1 || got[0] != "B" {
+		t.Errorf("unnormalized objects = %v, want [B]", got)
+	}
+
+	world.NormalizeAll()
+	if got := world.ValidateNormalization(1e-12); len(got) != 0 {
+		t.Errorf("all objects should be normalized, still off: %v", got)
+	}
+}
+
+func TestNormalizeAllIdempotent(t *testing.T) {
+	world := NewWorld(WithSize(6, 6))
+	obj := NewQuantumObject("A", gridDist(6, 6))
+	done := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1, {2, 2}: 1})
+	world.AddQuantumObject(obj)
+	world.AddQuantumObject(done)
+	done.CollapseWith(0)
+	done.CoordDist[[2]int{5, 5}] = 3
+
+	world.NormalizeAll()
+	first := copyDist(obj.CoordDist)
+	world.NormalizeAll()
+	for c, p := range first {
+		if math.Abs(obj.CoordDist[c]-p) > 1e-15 {
+			t.Errorf("renormalizing changed %v: %g -> %g", c, p, obj.CoordDist[c])
+		}
+	}
+	if done.CoordDist[[2]int{5, 5}] != 3 {
+		t.Error("collapsed objects should be skipped")
+	}
+}
+
+func TestValidationReport(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	world.AddQuantumObject(NewQuantumObject("ok", gridDist(3, 3)))
+	world.AddQuantumObject(NewQuantumObject("empty", map[[2]int]float64{{0, 0}: 0}))
+	world.AddQuantumObject(NewQuantumObject("negative", map[[2]int]float64{{0, 0}: 1, {1, 1}: -0.5}))
+	collapsed := NewQuantumObject("collapsed", map[[2]int]float64{{2, 2}: 1})
+	world.AddQuantumObject(collapsed)
+	collapsed.Collapse()
+	collapsed.CoordDist = map[[2]int]float64{{1, 1}: 1}
+
+	report := world.ValidationReport()
+	want := []ValidationError{
+		{ObjectName: "empty", Kind: ZeroDistribution},
+		{ObjectName: "negative", Kind: NegativeWeight},
+		{ObjectName: "collapsed", Kind: InvalidFinalCoord},
+	}
+	if len(report) != len(want) {
+		t.Fatalf("report = %v, want %d entries", report, len(want))
+	}
+	for i, e := range report {
+		if e.ObjectName != want[i].ObjectName || e.Kind != want[i].Kind || e.Detail == "" {
+			t.Errorf("entry %d = %+v, want %s for %q", i, e, want[i].Kind, want[i].ObjectName)
+		}
+	}
+
+	var ve ValidationError
+	if err := world.Validate(); !errors.As(err, &ve) || ve.Kind != ZeroDistribution {
+		t.Errorf("Validate should wrap ValidationError values, got %v", err)
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.SetStrictMode(true)
+	world.MeasureInteraction(a, b)
+
+	world.AddQuantumObject(NewQuantumObject("outside", map[[2]int]float64{{5, 5}: 1}))
+	defer func() {
+		if recover() == nil {
+			t.Error("strict mode should panic on an invalid world")
+		}
+	}()
+	world.CollapseAll()
+}
@@ -1,62 +1,493 @@
 package quantum
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"math"
 	"math/rand"
+	"reflect"
+	"slices"
+	"sync"
 )
 
 // QuantumObject хранит распределение вероятностей координат,
 // флаг коллапса и финальную координату.
+// Методы объекта потокобезопасны; прямое обращение к полям обходит блокировку.
+// Распределение хранится либо в CoordDist, либо в плотном представлении,
+// заданном SetDistribution (см. Distribution); в последнем случае CoordDist
+// равен nil, пока объект не изменят.
 type QuantumObject struct {
 	Name        string
 	CoordDist   map[[2]int]float64 // (x,y) -> вес (вероятность до нормировки)
 	IsCollapsed bool
 	FinalCoord  [2]int
+	Metadata    map[string]string // произвольные пользовательские метки; nil — меток нет
+
+	mu        sync.RWMutex       // защищает распределение, состояние коллапса и связи
+	dist      Distribution       // плотное хранилище весов; nil — веса в CoordDist
+	rng       *lockedRand        // собственный источник случайности; nil — источник мира или глобальный
+	entangled []link             // запутывающие связи с другими объектами
+	prior     map[[2]int]float64 // распределение непосредственно перед коллапсом
+	hooks     []func([2]int)     // обработчики коллапса объекта
+	strategy  CollapseStrategy   // выбор клетки коллапса; nil — RandomWeightedStrategy
+	world     *World             // мир, в который объект добавлен последним
+}
+
+// ObjectOption настраивает объект при создании в NewQuantumObject.
+type ObjectOption func(*QuantumObject)
+
+// WithMetadata добавляет объекту метку key=value.
+func WithMetadata(key, value string) ObjectOption {
+	return func(q *QuantumObject) {
+		if q.Metadata == nil {
+			q.Metadata = make(map[string]string)
+		}
+		q.Metadata[key] = value
+	}
+}
+
+// WithDistribution заменяет распределение, переданное в NewQuantumObject;
+// удобно, когда опции объекта собираются программно.
+func WithDistribution(dist map[[2]int]float64) ObjectOption {
+	return func(q *QuantumObject) {
+		q.CoordDist = dist
+	}
+}
+
+// WithRand задаёт собственный генератор объекта (см. SetRand).
+func WithRand(rng *rand.Rand) ObjectOption {
+	return func(q *QuantumObject) {
+		q.rng = newLockedRand(rng)
+	}
+}
+
+// WithCollapseStrategy задаёт стратегию коллапса (см. SetCollapseStrategy).
+func WithCollapseStrategy(s CollapseStrategy) ObjectOption {
+	return func(q *QuantumObject) {
+		q.strategy = s
+	}
+}
+
+// WithOnCollapse регистрирует обработчик коллапса (см. OnCollapse).
+func WithOnCollapse(fn func([2]int)) ObjectOption {
+	return func(q *QuantumObject) {
+		q.hooks = append(q.hooks, fn)
+	}
 }
 
 // NewQuantumObject создаёт новый квантовый объект с заданным распределением.
-func NewQuantumObject(name string, dist map[[2]int]float64) *QuantumObject {
-	return &QuantumObject{
+// Опции применяются по порядку; dist может быть nil, если распределение
+// задаётся WithDistribution.
+func NewQuantumObject(name string, dist map[[2]int]float64, opts ...ObjectOption) *QuantumObject {
+	q := &QuantumObject{
 		Name:      name,
 		CoordDist: dist,
 	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// NewQuantumObjectWithRand создаёт объект с собственным генератором случайных чисел,
+// что делает результат коллапса воспроизводимым при фиксированном зерне.
+func NewQuantumObjectWithRand(name string, dist map[[2]int]float64, rng *rand.Rand) *QuantumObject {
+	return NewQuantumObject(name, dist, WithRand(rng))
+}
+
+// SetRand задаёт собственный генератор объекта. nil возвращает поведение по умолчанию.
+// *rand.Rand не потокобезопасен: один генератор не следует передавать
+// нескольким объектам, измеряемым в разных горутинах.
+func (q *QuantumObject) SetRand(rng *rand.Rand) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rng = newLockedRand(rng)
+}
+
+// World возвращает мир, в который добавлен объект, или nil.
+func (q *QuantumObject) World() *World {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.world
+}
+
+// CloneSuffix добавляется к имени копии, создаваемой World.CloneObject.
+const CloneSuffix = "'"
+
+// Clone возвращает независимую копию объекта под тем же именем.
+// Распределение копируется глубоко; генератор случайных чисел общий с оригиналом,
+// запутывающие связи не копируются.
+func (q *QuantumObject) Clone() *QuantumObject {
+	return q.CloneAs(q.Name)
+}
+
+// CloneAs возвращает независимую копию объекта с именем name.
+func (q *QuantumObject) CloneAs(name string) *QuantumObject {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return &QuantumObject{
+		Name:        name,
+		CoordDist:   copyDist(q.CoordDist),
+		dist:        cloneStored(q.dist),
+		IsCollapsed: q.IsCollapsed,
+		FinalCoord:  q.FinalCoord,
+		Metadata:    maps.Clone(q.Metadata),
+		rng:         q.rng,
+		prior:       copyDist(q.prior),
+		strategy:    q.strategy,
+	}
+}
+
+// Reset возвращает объект в суперпозицию: снимает флаг коллапса, обнуляет
+// FinalCoord и восстанавливает распределение, сохранённое перед коллапсом.
+// Если снимка нет, CoordDist остаётся как есть и может быть заполнен вызывающим.
+func (q *QuantumObject) Reset() {
+	defer q.supportChanged()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.IsCollapsed = false
+	q.FinalCoord = [2]int{}
+	if q.prior != nil {
+		q.dist, q.CoordDist = nil, q.prior
+		q.prior = nil
+	}
+}
+
+// ResetWith возвращает объект в суперпозицию с новым распределением dist.
+// Карта копируется, поэтому последующие изменения dist не затрагивают объект.
+func (q *QuantumObject) ResetWith(dist map[[2]int]float64) {
+	defer q.supportChanged()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.IsCollapsed = false
+	q.FinalCoord = [2]int{}
+	q.prior = nil
+	q.dist, q.CoordDist = nil, copyDist(dist)
+}
+
+func copyDist(dist map[[2]int]float64) map[[2]int]float64 {
+	if dist == nil {
+		return nil
+	}
+	out := make(map[[2]int]float64, len(dist))
+	for c, w := range dist {
+		out[c] = w
+	}
+	return out
 }
 
 // NormalizeDistribution нормирует распределение так, чтобы сумма вероятностей стала 1.
 func (q *QuantumObject) NormalizeDistribution() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.normalizeLocked()
+}
+
+func (q *QuantumObject) normalizeLocked() {
+	q.sparseLocked()
+	if !q.scaleLocked() || q.world == nil || q.world.minProb <= 0 {
+		return
+	}
+	// порог применяется к нормированным вероятностям; наиболее вероятная
+	// клетка сохраняется, даже если и она ниже порога
+	floor := q.world.minProb
+	var best [2]int
+	bestP := math.Inf(-1)
+	for _, c := range sortedCoords(q.CoordDist) {
+		if p := q.CoordDist[c]; p > bestP {
+			best, bestP = c, p
+		}
+	}
+	dropped := false
+	for c, p := range q.CoordDist {
+		if p < floor && c != best {
+			delete(q.CoordDist, c)
+			dropped = true
+		}
+	}
+	if dropped {
+		q.scaleLocked()
+	}
+}
+
+// scaleLocked делит веса CoordDist на их сумму. Возвращает false, если
+// сумма неположительна и распределение не изменилось.
+func (q *QuantumObject) scaleLocked() bool {
 	total := 0.0
 	for _, w := range q.CoordDist {
 		total += w
 	}
-	if total > 0 {
-		for k, w := range q.CoordDist {
-			q.CoordDist[k] = w / total
+	if total <= 0 {
+		return false
+	}
+	for k, w := range q.CoordDist {
+		q.CoordDist[k] = w / total
+	}
+	return true
+}
+
+// Prune удаляет клетки, нормированная вероятность которых меньше threshold,
+// и перенормирует остаток. Возвращает число удалённых клеток.
+// Если ниже порога все клетки, остаётся одна наиболее вероятная (при равенстве —
+// первая в порядке sortedCoords). Коллапсированный объект не изменяется.
+func (q *QuantumObject) Prune(threshold float64) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.IsCollapsed {
+		return 0
+	}
+	q.normalizeLocked()
+	var best [2]int
+	bestP := math.Inf(-1)
+	for _, c := range sortedCoords(q.CoordDist) {
+		if p := q.CoordDist[c]; p > bestP {
+			best, bestP = c, p
+		}
+	}
+	keepBest := bestP < threshold
+	removed := 0
+	for c, p := range q.CoordDist {
+		if p < threshold && !(keepBest && c == best) {
+			delete(q.CoordDist, c)
+			removed++
 		}
 	}
+	q.normalizeLocked()
+	return removed
 }
 
 // Collapse выполняет коллапс волновой функции: выбирает случайную координату
 // согласно распределению вероятностей. Если объект уже коллапсирован, ничего не делает.
+// Клетки перебираются в порядке sortedCoords (по x, затем по y), поэтому при
+// одинаковых распределении и зерне исход одинаков при любом порядке заполнения карты.
 func (q *QuantumObject) Collapse() {
+	defer q.track("Collapse")()
+	q.collapseFrom(nil)
+}
+
+// collapseFrom выполняет коллапс, используя генератор объекта, а при его
+// отсутствии — fallback (генератор мира) или глобальный генератор.
+func (q *QuantumObject) collapseFrom(fallback *lockedRand) {
+	q.mu.Lock()
+	settled := q.collapseLocked(fallback)
+	q.mu.Unlock()
+	if settled {
+		q.settled(fallback)
+	}
+}
+
+// collapseLocked выбирает координату при захваченной блокировке и сообщает,
+// произошёл ли коллапс. Партнёров уведомляет вызывающий после снятия блокировки.
+func (q *QuantumObject) collapseLocked(fallback *lockedRand) bool {
+	if q.IsCollapsed {
+		return false
+	}
+	if q.strategy != nil {
+		return q.pickLocked(fallback)
+	}
+	return q.selectLocked(drawFloat(q.rng, fallback))
+}
+
+// CollapseWith выполняет коллапс с заданным значением r вместо случайного:
+// выбирается первая клетка в порядке sortedCoords, на которой накопленная
+// вероятность достигает r. r приводится к [0, 1). Позволяет проверять
+// выбор исхода напрямую. Уже коллапсированный объект не изменяется.
+func (q *QuantumObject) CollapseWith(r float64) {
+	r = min(max(r, 0), math.Nextafter(1, 0))
+	defer q.track("CollapseWith")()
+	q.mu.Lock()
+	settled := !q.IsCollapsed && q.selectLocked(r)
+	q.mu.Unlock()
+	if settled {
+		q.settled(nil)
+	}
+}
+
+// selectLocked коллапсирует объект в клетку inverseCDF по значению r,
+// так что непустое распределение всегда коллапсирует.
+func (q *QuantumObject) selectLocked(r float64) bool {
+	q.normalizeLocked()
+	coord, found := inverseCDF(q.CoordDist, r)
+	if found {
+		q.settleLocked(coord)
+	}
+	return found
+}
+
+// CollapseToRegion выполняет грубое измерение: объект оказывается где-то в
+// прямоугольнике [x0,x1]×[y0,y1] (границы включительно). Вес вне прямоугольника
+// отбрасывается, остаток нормируется; IsCollapsed остаётся false до полного Collapse.
+// Если в прямоугольнике нет вероятности, распределение не меняется и возвращается ошибка.
+func (q *QuantumObject) CollapseToRegion(x0, y0, x1, y1 int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	x0, x1 = min(x0, x1), max(x0, x1)
+	y0, y1 = min(y0, y1), max(y0, y1)
+	inside := func(c [2]int) bool {
+		return c[0] >= x0 && c[0] <= x1 && c[1] >= y0 && c[1] <= y1
+	}
+	if q.IsCollapsed {
+		if !inside(q.FinalCoord) {
+			return fmt.Errorf("%s is collapsed outside region (%d,%d)-(%d,%d)", q.Name, x0, y0, x1, y1)
+		}
+		return nil
+	}
+	restricted := make(map[[2]int]float64)
+	for c, w := range q.coordsLocked() {
+		if w > 0 && inside(c) {
+			restricted[c] = w
+		}
+	}
+	if len(restricted) == 0 {
+		return fmt.Errorf("%s has no probability in region (%d,%d)-(%d,%d)", q.Name, x0, y0, x1, y1)
+	}
+	q.CoordDist = restricted
+	q.normalizeLocked()
+	return nil
+}
+
+// MaxStrength — сила мягкого измерения, равносильная жёсткому коллапсу.
+var MaxStrength = math.Inf(1)
+
+// SoftMeasure выполняет слабое измерение около center: распределение умножается
+// на гауссов множитель exp(-strength·d²/2), где d — расстояние до center, и
+// нормируется; IsCollapsed не меняется. Повторные измерения постепенно
+// концентрируют вероятность. strength = MaxStrength коллапсирует объект в center.
+// Для объекта, добавленного в мир, расстояние учитывает его граничный режим.
+func (q *QuantumObject) SoftMeasure(center [2]int, strength float64) {
+	q.mu.Lock()
+	w := q.world
+	var fallback *lockedRand
+	if w != nil {
+		fallback = w.rng
+	}
+	if q.IsCollapsed || strength <= 0 {
+		q.mu.Unlock()
+		return
+	}
+	if math.IsInf(strength, 1) {
+		q.mu.Unlock()
+		q.pin(center, fallback)
+		return
+	}
+	dist2 := func(c [2]int) float64 {
+		if w != nil {
+			d := w.Distance(c, center)
+			return d * d
+		}
+		dx, dy := float64(c[0]-center[0]), float64(c[1]-center[1])
+		return dx*dx + dy*dy
+	}
+	// отсчёт от ближайшей к center клетки защищает от исчезновения всех весов при большой силе
+	q.sparseLocked()
+	nearest := math.Inf(1)
+	for c, p := range q.CoordDist {
+		if p > 0 {
+			nearest = min(nearest, dist2(c))
+		}
+	}
+	if !math.IsInf(nearest, 1) {
+		for c, p := range q.CoordDist {
+			q.CoordDist[c] = p * math.Exp(-strength*(dist2(c)-nearest)/2)
+		}
+		q.normalizeLocked()
+	}
+	q.mu.Unlock()
+}
+
+// pin коллапсирует объект в заданную координату и уведомляет партнёров.
+// Уже коллапсированный объект не изменяется.
+func (q *QuantumObject) pin(coord [2]int, fallback *lockedRand) {
+	q.mu.Lock()
 	if q.IsCollapsed {
+		q.mu.Unlock()
 		return
 	}
-	q.NormalizeDistribution()
-	r := rand.Float64()
-	cumulative := 0.0
-	for coord, prob := range q.CoordDist {
-		cumulative += prob
-		if r <= cumulative {
-			q.FinalCoord = coord
-			q.IsCollapsed = true
-			// заменяем распределение на дельта-функцию
-			q.CoordDist = map[[2]int]float64{coord: 1.0}
-			break
+	q.settleLocked(coord)
+	q.mu.Unlock()
+	q.settled(fallback)
+}
+
+// settleLocked фиксирует объект в coord.
+func (q *QuantumObject) settleLocked(coord [2]int) {
+	q.sparseLocked()
+	q.FinalCoord = coord
+	q.IsCollapsed = true
+	// заменяем распределение на дельта-функцию, сохранив прежнее для Reset
+	q.prior = q.CoordDist
+	q.CoordDist = map[[2]int]float64{coord: 1.0}
+}
+
+// OnCollapse регистрирует обработчик, вызываемый с FinalCoord сразу после
+// коллапса объекта. Обработчики вызываются синхронно в порядке регистрации.
+func (q *QuantumObject) OnCollapse(fn func([2]int)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.hooks = append(q.hooks, fn)
+}
+
+// settled вызывается после коллапса без удержания блокировки: уведомляет
+// обработчики объекта и мира, затем передаёт результат запутанным партнёрам.
+func (q *QuantumObject) settled(fallback *lockedRand) {
+	q.mu.RLock()
+	name, coord := q.Name, q.FinalCoord
+	hooks := slices.Clone(q.hooks)
+	w := q.world
+	q.mu.RUnlock()
+
+	if w != nil {
+		w.logEvent(Event{Kind: EventCollapse, Names: []string{name}, Coord: coord})
+	}
+	for _, fn := range hooks {
+		fn(coord)
+	}
+	if w != nil {
+		for _, fn := range w.collapseHooks() {
+			fn(q)
 		}
 	}
+	q.propagate(fallback)
+}
+
+// track передаёт коллапс объекта в World.track его мира (история и журнал).
+// Для уже коллапсированного объекта или объекта вне мира ничего не делает.
+func (q *QuantumObject) track(op string) func() {
+	q.mu.RLock()
+	w, collapsed := q.world, q.IsCollapsed
+	q.mu.RUnlock()
+	if collapsed {
+		return func() {}
+	}
+	return w.track(op, q)
+}
+
+// isCollapsed читает флаг коллапса под блокировкой.
+func (q *QuantumObject) isCollapsed() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.IsCollapsed
+}
+
+// sortedCoords возвращает координаты распределения в фиксированном порядке
+// (по x, затем по y), чтобы выбор при коллапсе не зависел от порядка обхода map.
+func sortedCoords(dist map[[2]int]float64) [][2]int {
+	coords := make([][2]int, 0, len(dist))
+	for c := range dist {
+		coords = append(coords, c)
+	}
+	slices.SortFunc(coords, func(a, b [2]int) int {
+		if a[0] != b[0] {
+			return cmp.Compare(a[0], b[0])
+		}
+		return cmp.Compare(a[1], b[1])
+	})
+	return coords
 }
 
 func (q *QuantumObject) String() string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
 	if q.IsCollapsed {
 		return fmt.Sprintf("<%s collapsed at (%d, %d)>",
 			q.Name, q.FinalCoord[0], q.FinalCoord[1])
@@ -65,60 +496,828 @@ func (q *QuantumObject) String() string {
 }
 
 // World — дискретное пространство размером Width×Height, содержащее объекты.
+// Методы мира потокобезопасны; прямое обращение к Objects обходит блокировку.
+// Размеры, граничный режим и правила эволюции считаются настройками
+// и не должны меняться, пока мир используется из нескольких горутин.
 type World struct {
-	Width   int
-	Height  int
-	Objects []*QuantumObject
+	Width        int
+	Height       int
+	Boundary     BoundaryMode // поведение на краях сетки; по умолчанию Bounded
+	Neighborhood Neighborhood // окрестность для Neighbors и Diffuse; по умолчанию VonNeumann
+	Objects      []*QuantumObject
+
+	// Evolution — правило эволюции для Step; nil означает GaussianDiffusion.
+	Evolution EvolutionFunc
+	// Decoherence — модель декогеренции, применяемая в Step после Evolution.
+	Decoherence DecoherenceModel
+
+	mu        sync.RWMutex  // защищает Objects, обработчики и индексы
+	rng       *lockedRand   // источник для объектов без собственного генератора
+	minProb   float64       // порог нормированной вероятности; 0 — не задан
+	index     *SpatialIndex // пространственный индекс; nil — не построен или сброшен
+	indexSize int           // размер корзины для перестройки сброшенного индекса
+	hooks     []func(*QuantumObject)
+	names     map[string][]*QuantumObject // индекс имён в порядке добавления; nil — не построен
+	namesFor  []*QuantumObject            // срез Objects, для которого построен names
+
+	history      []historyEntry // снимки для Undo, от старых к новым
+	historyDepth int            // предельная глубина history; 0 — запись выключена
+	audit        []AuditEvent   // журнал измерений
+	auditing     bool           // включён ли журнал
+	events       []Event        // журнал событий
+	eventing     bool           // включён ли журнал событий
+	strict       bool           // проверять ли мир после операций (SetStrictMode)
+}
+
+// WorldOption настраивает мир при создании в NewWorld.
+type WorldOption func(*World)
+
+// WithSize задаёт размеры мира.
+func WithSize(width, height int) WorldOption {
+	return func(w *World) {
+		w.Width, w.Height = width, height
+	}
+}
+
+// WithTopology задаёт режим границ мира.
+func WithTopology(mode BoundaryMode) WorldOption {
+	return func(w *World) {
+		w.Boundary = mode
+	}
+}
+
+// WithNeighborhood задаёт окрестность клетки.
+func WithNeighborhood(n Neighborhood) WorldOption {
+	return func(w *World) {
+		w.Neighborhood = n
+	}
+}
+
+// WithDecoherence задаёт равномерную декогеренцию с долей rate за единицу
+// времени (UniformDecoherence). Сетка модели — размеры мира после применения
+// всех опций, так что порядок относительно WithSize не важен.
+func WithDecoherence(rate float64) WorldOption {
+	return func(w *World) {
+		w.Decoherence = UniformDecoherence{Rate: rate}
+	}
+}
+
+// WithEvolution задаёт правило эволюции для Step и Simulate; nil оставляет
+// GaussianDiffusion.
+func WithEvolution(f EvolutionFunc) WorldOption {
+	return func(w *World) {
+		w.Evolution = f
+	}
+}
+
+// WithMinProbability задаёт порог вероятности (см. SetMinProbability).
+func WithMinProbability(floor float64) WorldOption {
+	return func(w *World) {
+		w.SetMinProbability(floor)
+	}
+}
+
+// WithHistory включает запись снимков глубиной depth (см. EnableHistory).
+func WithHistory(depth int) WorldOption {
+	return func(w *World) {
+		w.EnableHistory(depth)
+	}
+}
+
+// WithStrictMode включает проверку мира после операций (см. SetStrictMode).
+func WithStrictMode(strict bool) WorldOption {
+	return func(w *World) {
+		w.SetStrictMode(strict)
+	}
+}
+
+// WithRNG задаёт генератор мира (см. SetRand).
+func WithRNG(rng *rand.Rand) WorldOption {
+	return func(w *World) {
+		w.SetRand(rng)
+	}
+}
+
+// NewWorld создаёт мир и применяет опции по порядку. Без опций получается
+// мир 0×0; нулевое значение World тоже пригодно к использованию.
+func NewWorld(opts ...WorldOption) *World {
+	w := &World{}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if d, ok := w.Decoherence.(UniformDecoherence); ok && d.Width == 0 && d.Height == 0 {
+		d.Width, d.Height = w.Width, w.Height
+		w.Decoherence = d
+	}
+	return w
+}
+
+// NewWorldSimple создаёт мир заданного размера; равносильно
+// NewWorld(WithSize(width, height)).
+func NewWorldSimple(width, height int) *World {
+	return NewWorld(WithSize(width, height))
 }
 
-// NewWorld создаёт новый мир заданного размера.
-func NewWorld(width, height int) *World {
-	return &World{Width: width, Height: height}
+// NewWorldWithRand создаёт мир, коллапсы в котором берут случайные числа из rng
+// (если у объекта нет собственного генератора).
+func NewWorldWithRand(width, height int, rng *rand.Rand) *World {
+	return NewWorld(WithSize(width, height), WithRNG(rng))
+}
+
+// SetRand задаёт генератор мира. nil возвращает глобальный генератор.
+func (w *World) SetRand(rng *rand.Rand) {
+	w.rng = newLockedRand(rng)
+}
+
+// SetMinProbability задаёт порог: при нормировке и коллапсе объектов мира
+// клетки, нормированная вероятность которых ниже floor, удаляются из
+// распределения, а остаток перенормируется. Наиболее вероятная клетка
+// сохраняется всегда, так что распределение не становится пустым. Это
+// отсекает хвосты вроде гауссовых, где веса близки к антипереполнению.
+// По умолчанию порог равен 0 и на распределения не влияет; неположительный
+// floor отключает порог.
+func (w *World) SetMinProbability(floor float64) {
+	w.minProb = max(floor, 0)
+}
+
+// MinProbability возвращает текущий порог вероятности мира; 0 — порог не задан.
+func (w *World) MinProbability() float64 {
+	return w.minProb
 }
 
 // AddQuantumObject добавляет объект в мир.
 func (w *World) AddQuantumObject(obj *QuantumObject) {
+	w.attach(obj)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fresh := w.namesFresh()
 	w.Objects = append(w.Objects, obj)
+	if fresh {
+		w.names[obj.Name] = append(w.names[obj.Name], obj)
+		w.namesFor = w.Objects
+	}
+	w.invalidateIndexLocked()
+	w.logEventLocked(Event{Kind: EventCreate, Names: []string{obj.Name}})
+}
+
+// attach привязывает obj к миру: его коллапсы используют генератор мира,
+// а изменения распределения сбрасывают пространственный индекс.
+// Вызывается без удержания блокировки мира.
+func (w *World) attach(obj *QuantumObject) {
+	obj.mu.Lock()
+	obj.world = w
+	obj.mu.Unlock()
+}
+
+// RemoveQuantumObject удаляет obj из мира (по указателю) и сообщает, был ли он найден.
+// Запутывающие связи obj с другими объектами разрываются.
+func (w *World) RemoveQuantumObject(obj *QuantumObject) bool {
+	return w.remove(func(_ int, o *QuantumObject) bool { return o == obj }) > 0
+}
+
+// RemoveByName удаляет все объекты с именем name и возвращает их число.
+func (w *World) RemoveByName(name string) int {
+	return w.remove(func(_ int, o *QuantumObject) bool { return o.Name == name })
+}
+
+// remove удаляет объекты, для которых match истинно, сбрасывает индексы
+// и разрывает связи удалённых объектов с остальными.
+func (w *World) remove(match func(i int, obj *QuantumObject) bool) int {
+	w.mu.Lock()
+	var removed []*QuantumObject
+	kept := w.Objects[:0]
+	for i, o := range w.Objects {
+		if match(i, o) {
+			removed = append(removed, o)
+		} else {
+			kept = append(kept, o)
+		}
+	}
+	clear(w.Objects[len(kept):])
+	w.Objects = kept
+	if len(removed) > 0 {
+		w.names = nil
+		w.invalidateIndexLocked()
+	}
+	w.mu.Unlock()
+
+	for _, obj := range removed {
+		obj.mu.Lock()
+		links := obj.entangled
+		obj.entangled = nil
+		if obj.world == w {
+			obj.world = nil
+		}
+		obj.mu.Unlock()
+		for _, l := range links {
+			l.partner.unlink(obj)
+		}
+	}
+	return len(removed)
+}
+
+// FilterByMetadata возвращает объекты с меткой key=value в порядке добавления.
+func (w *World) FilterByMetadata(key, value string) []*QuantumObject {
+	var out []*QuantumObject
+	for _, obj := range w.objects() {
+		obj.mu.RLock()
+		v, ok := obj.Metadata[key]
+		obj.mu.RUnlock()
+		if ok && v == value {
+			out = append(out, obj)
+		}
+	}
+	return out
+}
+
+// ObjectNotFoundError сообщает, что в мире нет объекта с именем Name.
+type ObjectNotFoundError struct {
+	Name string
+}
+
+func (e *ObjectNotFoundError) Error() string {
+	return fmt.Sprintf("object %q not found", e.Name)
+}
+
+// RemoveObject удаляет первый добавленный объект с именем name.
+// Если такого нет, возвращает *ObjectNotFoundError.
+func (w *World) RemoveObject(name string) error {
+	found := false
+	w.remove(func(_ int, o *QuantumObject) bool {
+		if found || o.Name != name {
+			return false
+		}
+		found = true
+		return true
+	})
+	if !found {
+		return &ObjectNotFoundError{Name: name}
+	}
+	return nil
+}
+
+// RemoveObjectByIndex удаляет объект Objects[i].
+func (w *World) RemoveObjectByIndex(i int) error {
+	if w.remove(func(j int, _ *QuantumObject) bool { return j == i }) == 0 {
+		return fmt.Errorf("object index %d out of range", i)
+	}
+	return nil
+}
+
+// Find возвращает первый добавленный объект с именем name.
+// Имена не обязаны быть уникальными; все совпадения возвращает FindAll.
+func (w *World) Find(name string) (*QuantumObject, bool) {
+	objs := w.FindAll(name)
+	if len(objs) == 0 {
+		return nil, false
+	}
+	return objs[0], true
+}
+
+// FindAll возвращает все объекты с именем name в порядке добавления.
+// Поиск идёт по индексу имён; индекс перестраивается, если Objects заменён
+// или его длина изменилась в обход AddQuantumObject. Переименованный после добавления объект
+// не возвращается по старому имени, но по новому находится лишь после
+// следующего изменения Objects.
+func (w *World) FindAll(name string) []*QuantumObject {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	objs := w.nameIndex()[name]
+	for _, obj := range objs {
+		if obj.Name != name {
+			w.names = nil
+			objs = w.nameIndex()[name]
+			break
+		}
+	}
+	return slices.Clone(objs)
+}
+
+// nameIndex возвращает индекс имён, перестраивая его, если он сброшен или
+// построен для другого среза Objects. Вызывается под блокировкой мира на запись.
+func (w *World) nameIndex() map[string][]*QuantumObject {
+	if !w.namesFresh() {
+		w.names = make(map[string][]*QuantumObject, len(w.Objects))
+		for _, obj := range w.Objects {
+			w.names[obj.Name] = append(w.names[obj.Name], obj)
+		}
+		w.namesFor = w.Objects
+	}
+	return w.names
+}
+
+// namesFresh сообщает, построен ли индекс имён для текущего среза Objects:
+// совпадают длина и начало массива, так что замена среза целиком
+// (в том числе срезом той же длины) сбрасывает индекс.
+func (w *World) namesFresh() bool {
+	if w.names == nil || len(w.namesFor) != len(w.Objects) {
+		return false
+	}
+	return len(w.Objects) == 0 || &w.namesFor[0] == &w.Objects[0]
+}
+
+// OnCollapse регистрирует обработчик, вызываемый синхронно, когда любой объект
+// мира коллапсирует — через Collapse, CollapseAll, измерение или запутанность.
+// Обработчик получает объект с уже установленными FinalCoord и IsCollapsed.
+func (w *World) OnCollapse(fn func(*QuantumObject)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks = append(w.hooks, fn)
+}
+
+// RemoveOnCollapse снимает первый зарегистрированный обработчик с той же функцией.
+// Замыкания одного литерала неразличимы, поэтому снимается первое из них.
+func (w *World) RemoveOnCollapse(fn func(*QuantumObject)) {
+	target := reflect.ValueOf(fn).Pointer()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, h := range w.hooks {
+		if reflect.ValueOf(h).Pointer() == target {
+			w.hooks = slices.Delete(w.hooks, i, i+1)
+			return
+		}
+	}
+}
+
+// CloneObject копирует первый объект с именем name и добавляет копию в мир
+// под именем name+CloneSuffix.
+func (w *World) CloneObject(name string) (*QuantumObject, error) {
+	obj, ok := w.Find(name)
+	if !ok {
+		return nil, &ObjectNotFoundError{Name: name}
+	}
+	clone := obj.CloneAs(name + CloneSuffix)
+	w.AddQuantumObject(clone)
+	return clone, nil
 }
 
 // MeasureInteraction выполняет взаимодействие между двумя объектами.
-// Взаимодействие происходит только в точках совпадения координат.
-func (w *World) MeasureInteraction(obj1, obj2 *QuantumObject) {
+// Взаимодействие происходит только в точках совпадения координат;
+// в режиме Toroidal координаты сравниваются по модулю размеров мира.
+// Обходится только меньшее из распределений: O(min(N, M)).
+// Возвращает false, если измерение не произошло: у распределений нет общих
+// клеток или оба объекта уже коллапсированы.
+func (w *World) MeasureInteraction(obj1, obj2 *QuantumObject) bool {
+	return w.measure("MeasureInteraction", obj1, obj2, exactJoint)
+}
+
+// MeasureInteractionN моделирует n повторных наблюдений: каждое наблюдение
+// независимо умножает вес общей клетки на p1·p2, поэтому после n раундов без
+// промежуточного коллапса совместный вес равен (p1·p2)^n и с каждым раундом
+// сильнее выделяет наиболее вероятные общие клетки. Затем оба объекта
+// коллапсируют один раз. n <= 1 равносильно MeasureInteraction.
+// Результат — как у MeasureInteraction.
+func (w *World) MeasureInteractionN(obj1, obj2 *QuantumObject, n int) bool {
+	return w.measure("MeasureInteractionN", obj1, obj2, powJoint(exactJoint, n))
+}
+
+// MeasureInteractionReliability — неточное наблюдение: каждый объект получает
+// смесь reliability·(распределение после MeasureInteraction) +
+// (1-reliability)·(прежнее распределение), нормированную, и остаётся
+// в суперпозиции. reliability >= 1 равносильно MeasureInteraction (с коллапсом),
+// reliability <= 0 ничего не меняет. Коллапсированный объект не изменяется;
+// если общих клеток нет, наблюдение не происходит. Возвращает, изменились ли
+// распределения, как MeasureInteraction.
+func (w *World) MeasureInteractionReliability(obj1, obj2 *QuantumObject, reliability float64) bool {
+	switch {
+	case reliability >= 1:
+		return w.MeasureInteraction(obj1, obj2)
+	case !(reliability > 0):
+		return false
+	}
+
+	defer w.track("MeasureInteractionReliability", obj1, obj2)()
+	lockPair(obj1, obj2)
 	if obj1.IsCollapsed && obj2.IsCollapsed {
-		return
+		unlockPair(obj1, obj2)
+		return false
+	}
+	obj1.normalizeLocked()
+	obj2.normalizeLocked()
+	prior1, prior2 := w.wrapDist(obj1.CoordDist), w.wrapDist(obj2.CoordDist)
+	post1, post2 := exactJoint(prior1, prior2)
+	if len(post1) > 0 {
+		if !obj1.IsCollapsed {
+			obj1.CoordDist = blendDist(post1, prior1, reliability)
+		}
+		if !obj2.IsCollapsed {
+			obj2.CoordDist = blendDist(post2, prior2, reliability)
+		}
+	}
+	unlockPair(obj1, obj2)
+	w.logEvent(Event{Kind: EventMeasure, Names: []string{obj1.Name, obj2.Name}, Support: len(post1)})
+	return len(post1) > 0
+}
+
+// blendDist возвращает weight·post/|post| + (1-weight)·prior для нормированного prior.
+func blendDist(post, prior map[[2]int]float64, weight float64) map[[2]int]float64 {
+	total := SparseDistribution(post).Total()
+	out := make(map[[2]int]float64, len(prior))
+	for c, p := range prior {
+		out[c] = (1 - weight) * p
+	}
+	for c, p := range post {
+		out[c] += weight * p / total
+	}
+	return out
+}
+
+// powJoint возводит совместные веса joint в степень n. Веса предварительно
+// делятся на наибольший, чтобы при больших n они не исчезали.
+func powJoint(joint jointFunc, n int) jointFunc {
+	return func(d1, d2 map[[2]int]float64) (map[[2]int]float64, map[[2]int]float64) {
+		j1, j2 := joint(d1, d2)
+		if n <= 1 {
+			return j1, j2
+		}
+		for _, j := range []map[[2]int]float64{j1, j2} {
+			peak := 0.0
+			for _, p := range j {
+				peak = max(peak, p)
+			}
+			for c, p := range j {
+				j[c] = math.Pow(p/peak, float64(n))
+			}
+		}
+		return j1, j2
+	}
+}
+
+// MeasureInteractionGroup — взаимодействие нескольких объектов в одной клетке
+// (обобщение MeasureInteraction): совместный вес клетки — произведение
+// вероятностей всех объектов в ней. По этому весу выбирается одна клетка,
+// и все несколлапсированные участники коллапсируют в неё; уже коллапсированные
+// оставляют вес только в своей FinalCoord и не изменяются. Если общей для всех
+// клетки нет, измерение не происходит. Повторы объектов в objs учитываются один раз.
+// Результат — как у MeasureInteraction.
+func (w *World) MeasureInteractionGroup(objs ...*QuantumObject) bool {
+	if len(objs) == 0 {
+		return false
+	}
+	defer w.track("MeasureInteractionGroup", objs...)()
+	locked := lockAll(objs)
+	allCollapsed := true
+	for _, obj := range locked {
+		allCollapsed = allCollapsed && obj.IsCollapsed
+	}
+	if allCollapsed {
+		unlockAll(locked)
+		return false
+	}
+	var joint map[[2]int]float64
+	for _, obj := range locked {
+		obj.normalizeLocked()
+		d := w.wrapDist(obj.CoordDist)
+		if joint == nil {
+			joint = copyDist(d)
+			continue
+		}
+		joint, _ = exactJoint(joint, d)
+	}
+	ev := Event{Kind: EventMeasure, Support: len(joint)}
+	for _, obj := range objs {
+		ev.Names = append(ev.Names, obj.Name)
+	}
+	total := SparseDistribution(joint).Total()
+	if total <= 0 {
+		unlockAll(locked)
+		w.logEvent(ev)
+		return false
+	}
+	coord, _ := inverseCDF(joint, drawFloat(locked[0].rng, w.rng)*total)
+	var settled []*QuantumObject
+	for _, obj := range locked {
+		if !obj.IsCollapsed {
+			obj.settleLocked(coord)
+			settled = append(settled, obj)
+		}
+	}
+	unlockAll(locked)
+	w.logEvent(ev)
+	for _, obj := range settled {
+		obj.settled(w.rng)
+	}
+	return true
+}
+
+// exactJoint — совместное распределение для взаимодействия в одной клетке:
+// p1(c)·p2(c) на общих клетках, найденных обходом меньшего распределения.
+func exactJoint(d1, d2 map[[2]int]float64) (map[[2]int]float64, map[[2]int]float64) {
+	small, large := d1, d2
+	if len(large) < len(small) {
+		small, large = large, small
+	}
+	joint := make(map[[2]int]float64)
+	for c, p1 := range small {
+		if p2 := large[c]; p1 > 0 && p2 > 0 {
+			joint[c] = p1 * p2
+		}
+	}
+	return joint, copyDist(joint)
+}
+
+// MeasureInteractionWithin — взаимодействие на расстоянии: пары клеток (c1, c2)
+// с Distance(c1, c2) <= radius получают вес p1·p2·exp(-d²/(2·radius²)).
+// Новые распределения объектов — маргиналы этого совместного веса, после чего
+// оба объекта коллапсируют. radius = 0 воспроизводит MeasureInteraction.
+// При построенном пространственном индексе (BuildSpatialIndex) в режиме Bounded
+// перебираются только клетки obj2 из корзин рядом с каждой клеткой obj1.
+// Результат — как у MeasureInteraction.
+func (w *World) MeasureInteractionWithin(obj1, obj2 *QuantumObject, radius float64) bool {
+	kernel := w.radiusKernel(radius)
+	joint := w.pairJoint(kernel)
+	if idx := w.spatialIndex(); idx != nil && w.Boundary == Bounded {
+		if _, ok := idx.cells[obj2]; ok {
+			joint = w.indexedPairJoint(idx, obj2, radius, kernel)
+		}
+	}
+	return w.measure("MeasureInteractionWithin", obj1, obj2, joint)
+}
+
+// radiusKernel — ядро exp(-d²/(2·radius²)) для пар на расстоянии не больше radius;
+// при radius = 0 взаимодействуют только совпадающие клетки.
+func (w *World) radiusKernel(radius float64) Kernel {
+	return func(c1, c2 [2]int) float64 {
+		d := w.Distance(c1, c2)
+		switch {
+		case d > radius:
+			return 0
+		case radius == 0:
+			return 1
+		}
+		return math.Exp(-d * d / (2 * radius * radius))
+	}
+}
+
+// MeasureInteractionRadius — взаимодействие на расстоянии с совместным исходом:
+// пара клеток (c1, c2) выбирается случайно с весом p1·p2·exp(-d²/(2·radius²))
+// среди пар с Distance(c1, c2) <= radius, после чего obj1 коллапсирует в c1,
+// а obj2 — в c2. В отличие от MeasureInteractionWithin исходы объектов согласованы.
+// radius = 0 сводится к взаимодействию в одной клетке.
+// Результат — как у MeasureInteraction.
+func (w *World) MeasureInteractionRadius(obj1, obj2 *QuantumObject, radius float64) bool {
+	kernel := w.radiusKernel(radius)
+
+	defer w.track("MeasureInteractionRadius", obj1, obj2)()
+	lockPair(obj1, obj2)
+	if obj1.IsCollapsed && obj2.IsCollapsed {
+		unlockPair(obj1, obj2)
+		return false
+	}
+	obj1.normalizeLocked()
+	obj2.normalizeLocked()
+	d1, d2 := w.wrapDist(obj1.CoordDist), w.wrapDist(obj2.CoordDist)
+
+	type pair struct{ c1, c2 [2]int }
+	var pairs []pair
+	var weights []float64
+	total := 0.0
+	for _, c1 := range sortedCoords(d1) {
+		for _, c2 := range sortedCoords(d2) {
+			if weight := d1[c1] * d2[c2] * kernel(c1, w.nearest(c1, c2)); weight > 0 {
+				pairs = append(pairs, pair{c1, c2})
+				weights = append(weights, weight)
+				total += weight
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		unlockPair(obj1, obj2)
+		return false
 	}
-	obj1.NormalizeDistribution()
-	obj2.NormalizeDistribution()
 
-	newDist1 := make(map[[2]int]float64)
-	newDist2 := make(map[[2]int]float64)
+	r := drawFloat(obj1.rng, w.rng) * total
+	chosen := pairs[len(pairs)-1]
+	cumulative := 0.0
+	for i, p := range pairs {
+		cumulative += weights[i]
+		if r <= cumulative {
+			chosen = p
+			break
+		}
+	}
+	settled1, settled2 := !obj1.IsCollapsed, !obj2.IsCollapsed
+	if settled1 {
+		obj1.settleLocked(chosen.c1)
+	}
+	if settled2 {
+		obj2.settleLocked(chosen.c2)
+	}
+	unlockPair(obj1, obj2)
+	if settled1 {
+		obj1.settled(w.rng)
+	}
+	if settled2 {
+		obj2.settled(w.rng)
+	}
+	return true
+}
+
+// jointFunc строит по нормированным распределениям двух объектов их новые
+// (ненормированные) распределения после взаимодействия.
+type jointFunc func(d1, d2 map[[2]int]float64) (map[[2]int]float64, map[[2]int]float64)
 
-	for c1, p1 := range obj1.CoordDist {
-		for c2, p2 := range obj2.CoordDist {
-			if c1 == c2 && p1 > 0 && p2 > 0 {
-				w := p1 * p2
-				if w > 0 {
-					newDist1[c1] += w
-					newDist2[c2] += w
+// pairJoint возвращает jointFunc, перебирающую все пары клеток с весом
+// p1·p2·kernel(c1,c2); новые распределения — маргиналы совместного веса.
+// Ядру передаётся ближайший к c1 образ c2, так что расстояние в нём
+// совпадает с Distance и в режиме Toroidal.
+func (w *World) pairJoint(kernel Kernel) jointFunc {
+	return func(d1, d2 map[[2]int]float64) (map[[2]int]float64, map[[2]int]float64) {
+		newDist1 := make(map[[2]int]float64)
+		newDist2 := make(map[[2]int]float64)
+		for c1, p1 := range d1 {
+			for c2, p2 := range d2 {
+				if p1 <= 0 || p2 <= 0 {
+					continue
+				}
+				if weight := p1 * p2 * kernel(c1, w.nearest(c1, c2)); weight > 0 {
+					newDist1[c1] += weight
+					newDist2[c2] += weight
 				}
 			}
 		}
+		return newDist1, newDist2
+	}
+}
+
+// measure выполняет общую часть измерения: нормирует оба распределения,
+// строит новые распределения через joint, заменяет ими старые и коллапсирует
+// оба объекта. Блокировки обоих объектов удерживаются на всё время измерения.
+// op — имя операции для истории и журнала. Возвращает, произошло ли измерение.
+func (w *World) measure(op string, obj1, obj2 *QuantumObject, joint jointFunc) bool {
+	defer w.track(op, obj1, obj2)()
+	lockPair(obj1, obj2)
+	if obj1.IsCollapsed && obj2.IsCollapsed {
+		unlockPair(obj1, obj2)
+		return false
 	}
+	obj1.normalizeLocked()
+	obj2.normalizeLocked()
+
+	newDist1, newDist2 := joint(w.wrapDist(obj1.CoordDist), w.wrapDist(obj2.CoordDist))
+
+	ev := Event{Kind: EventMeasure, Names: []string{obj1.Name, obj2.Name}, Support: len(newDist1)}
 
 	// Если нет общих точек, взаимодействие не происходит.
 	if len(newDist1) == 0 || len(newDist2) == 0 {
-		return
+		unlockPair(obj1, obj2)
+		w.logEvent(ev)
+		return false
 	}
 
 	obj1.CoordDist = newDist1
 	obj2.CoordDist = newDist2
-	obj1.Collapse()
-	obj2.Collapse()
+	settled1 := obj1.collapseLocked(w.rng)
+	settled2 := obj2.collapseLocked(w.rng)
+	unlockPair(obj1, obj2)
+	w.logEvent(ev)
+	if settled1 {
+		obj1.settled(w.rng)
+	}
+	if settled2 {
+		obj2.settled(w.rng)
+	}
+	return true
+}
+
+// ResetAll возвращает все объекты мира в суперпозицию (см. QuantumObject.Reset).
+func (w *World) ResetAll() {
+	for _, obj := range w.objects() {
+		obj.Reset()
+	}
+	w.invalidateIndex()
+}
+
+// ResetAllWith возвращает все объекты в суперпозицию с распределениями,
+// которые distFactory строит по имени объекта.
+func (w *World) ResetAllWith(distFactory func(name string) map[[2]int]float64) {
+	for _, obj := range w.objects() {
+		obj.ResetWith(distFactory(obj.Name))
+	}
+	w.invalidateIndex()
+}
+
+// PruneAll вызывает Prune для всех несколлапсированных объектов мира
+// и возвращает общее число удалённых клеток.
+func (w *World) PruneAll(threshold float64) int {
+	removed := 0
+	for _, obj := range w.objects() {
+		removed += obj.Prune(threshold)
+	}
+	return removed
 }
 
 // CollapseAll коллапсирует все объекты в мире.
 func (w *World) CollapseAll() {
-	for _, obj := range w.Objects {
-		obj.Collapse()
+	objs := w.objects()
+	defer w.track("CollapseAll", objs...)()
+	for _, obj := range objs {
+		obj.collapseFrom(w.rng)
+	}
+}
+
+// CollapsedCount возвращает число коллапсированных объектов мира.
+func (w *World) CollapsedCount() int {
+	n := 0
+	for _, obj := range w.objects() {
+		if obj.isCollapsed() {
+			n++
+		}
+	}
+	return n
+}
+
+// UncollapsedCount возвращает число объектов мира, ещё находящихся в суперпозиции.
+func (w *World) UncollapsedCount() int {
+	return len(w.objects()) - w.CollapsedCount()
+}
+
+// AllCollapsed сообщает, коллапсированы ли все объекты мира;
+// для пустого мира — true. Останавливается на первом несколлапсированном.
+func (w *World) AllCollapsed() bool {
+	for _, obj := range w.objects() {
+		if !obj.isCollapsed() {
+			return false
+		}
+	}
+	return true
+}
+
+// ForEach вызывает fn для каждого объекта мира в порядке Objects. Перебирается
+// снимок списка, поэтому fn может добавлять и удалять объекты.
+func (w *World) ForEach(fn func(*QuantumObject)) {
+	for _, obj := range w.objects() {
+		fn(obj)
+	}
+}
+
+// ForEachCollapsed вызывает fn для каждого коллапсированного объекта.
+func (w *World) ForEachCollapsed(fn func(*QuantumObject)) {
+	w.ForEach(func(obj *QuantumObject) {
+		if obj.isCollapsed() {
+			fn(obj)
+		}
+	})
+}
+
+// ForEachUncollapsed вызывает fn для каждого объекта в суперпозиции.
+func (w *World) ForEachUncollapsed(fn func(*QuantumObject)) {
+	w.ForEach(func(obj *QuantumObject) {
+		if !obj.isCollapsed() {
+			fn(obj)
+		}
+	})
+}
+
+// Filter возвращает новый срез объектов, для которых predicate возвращает true,
+// в порядке Objects. Мир не меняется.
+func (w *World) Filter(predicate func(*QuantumObject) bool) []*QuantumObject {
+	var out []*QuantumObject
+	w.ForEach(func(obj *QuantumObject) {
+		if predicate(obj) {
+			out = append(out, obj)
+		}
+	})
+	return out
+}
+
+// Map возвращает новый мир с настройками w, в который добавлены результаты
+// transform для каждого объекта. transform получает копию объекта (см. Clone),
+// поэтому может менять её на месте; nil исключает объект из нового мира.
+// Исходный мир не меняется.
+func (w *World) Map(transform func(*QuantumObject) *QuantumObject) *World {
+	c, _ := w.clone()
+	copies := c.Objects
+	c.Objects = nil
+	c.names = nil
+	for _, obj := range copies {
+		if out := transform(obj); out != nil {
+			c.AddQuantumObject(out)
+		}
+	}
+	return c
+}
+
+// CollapseByPriority коллапсирует объекты по очереди в порядке приоритетов:
+// меньшее число — раньше. Объекты, чьих имён нет в priorities, коллапсируют
+// последними в исходном порядке. Порядок w.Objects не меняется.
+func (w *World) CollapseByPriority(priorities map[string]int) {
+	objs := w.objects()
+	defer w.track("CollapseByPriority", objs...)()
+	slices.SortStableFunc(objs, func(a, b *QuantumObject) int {
+		pa, okA := priorities[a.Name]
+		pb, okB := priorities[b.Name]
+		switch {
+		case okA && okB:
+			return cmp.Compare(pa, pb)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return 0
+	})
+	for _, obj := range objs {
+		obj.collapseFrom(w.rng)
 	}
 }
@@ -0,0 +1,253 @@
+package quantum
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// QuantumObject3D — объёмный аналог QuantumObject: распределение задано
+// на координатах (x,y,z).
+type QuantumObject3D struct {
+	Name        string
+	CoordDist   map[[3]int]float64 // (x,y,z) -> вес (вероятность до нормировки)
+	IsCollapsed bool
+	FinalCoord  [3]int
+
+	rng *lockedRand // собственный источник случайности; nil — источник мира или глобальный
+}
+
+// NewQuantumObject3D создаёт новый объёмный квантовый объект с заданным распределением.
+func NewQuantumObject3D(name string, dist map[[3]int]float64) *QuantumObject3D {
+	return &QuantumObject3D{
+		Name:      name,
+		CoordDist: dist,
+	}
+}
+
+// NewQuantumObject3DWithRand создаёт объёмный объект с собственным генератором.
+func NewQuantumObject3DWithRand(name string, dist map[[3]int]float64, rng *rand.Rand) *QuantumObject3D {
+	q := NewQuantumObject3D(name, dist)
+	q.rng = newLockedRand(rng)
+	return q
+}
+
+// Lift переносит плоский объект в объёмный мир, помещая всё его
+// распределение в слой z.
+func Lift(obj *QuantumObject, z int) *QuantumObject3D {
+	obj.mu.RLock()
+	defer obj.mu.RUnlock()
+	dist := make(map[[3]int]float64, len(obj.CoordDist))
+	for c, p := range obj.CoordDist {
+		dist[[3]int{c[0], c[1], z}] = p
+	}
+	q := NewQuantumObject3D(obj.Name, dist)
+	q.rng = obj.rng
+	q.IsCollapsed = obj.IsCollapsed
+	q.FinalCoord = [3]int{obj.FinalCoord[0], obj.FinalCoord[1], z}
+	return q
+}
+
+// SetRand задаёт собственный генератор объекта. nil возвращает поведение по умолчанию.
+func (q *QuantumObject3D) SetRand(rng *rand.Rand) {
+	q.rng = newLockedRand(rng)
+}
+
+// NormalizeDistribution нормирует распределение так, чтобы сумма вероятностей стала 1.
+func (q *QuantumObject3D) NormalizeDistribution() {
+	total := 0.0
+	for _, w := range q.CoordDist {
+		total += w
+	}
+	if total > 0 {
+		for k, w := range q.CoordDist {
+			q.CoordDist[k] = w / total
+		}
+	}
+}
+
+// Collapse выбирает случайную координату согласно распределению.
+// Если объект уже коллапсирован, ничего не делает.
+func (q *QuantumObject3D) Collapse() {
+	q.collapseFrom(nil)
+}
+
+func (q *QuantumObject3D) collapseFrom(fallback *lockedRand) {
+	if q.IsCollapsed {
+		return
+	}
+	q.NormalizeDistribution()
+	r := drawFloat(q.rng, fallback)
+	cumulative := 0.0
+	var chosen [3]int
+	found := false
+	for _, coord := range sortedCoords3D(q.CoordDist) {
+		if q.CoordDist[coord] <= 0 {
+			continue
+		}
+		// последняя клетка с весом — запасной выбор на случай ошибки округления
+		cumulative += q.CoordDist[coord]
+		chosen, found = coord, true
+		if r <= cumulative {
+			break
+		}
+	}
+	if found {
+		q.FinalCoord = chosen
+		q.IsCollapsed = true
+		q.CoordDist = map[[3]int]float64{chosen: 1.0}
+	}
+}
+
+func (q *QuantumObject3D) String() string {
+	if q.IsCollapsed {
+		return fmt.Sprintf("<%s collapsed at (%d, %d, %d)>",
+			q.Name, q.FinalCoord[0], q.FinalCoord[1], q.FinalCoord[2])
+	}
+	return fmt.Sprintf("<%s in superposition (uncollapsed)>", q.Name)
+}
+
+// sortedCoords3D — порядок обхода для коллапса: по x, затем y, затем z.
+func sortedCoords3D(dist map[[3]int]float64) [][3]int {
+	coords := make([][3]int, 0, len(dist))
+	for c := range dist {
+		coords = append(coords, c)
+	}
+	slices.SortFunc(coords, func(a, b [3]int) int {
+		for i := range a {
+			if a[i] != b[i] {
+				return cmp.Compare(a[i], b[i])
+			}
+		}
+		return 0
+	})
+	return coords
+}
+
+// UniformDistribution3D возвращает равномерное распределение по всем клеткам
+// объёма width×height×depth.
+func UniformDistribution3D(width, height, depth int) map[[3]int]float64 {
+	dist := make(map[[3]int]float64, width*height*depth)
+	if width*height*depth == 0 {
+		return dist
+	}
+	p := 1.0 / float64(width*height*depth)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			for z := 0; z < depth; z++ {
+				dist[[3]int{x, y, z}] = p
+			}
+		}
+	}
+	return dist
+}
+
+// GaussFactor3D — гауссов множитель exp(-|c-center|²/(2σ²)) для объёмных координат.
+// При sigma <= 0 множитель равен 1 в center и 0 в остальных клетках.
+func GaussFactor3D(c, center [3]int, sigma float64) float64 {
+	if sigma <= 0 {
+		if c == center {
+			return 1
+		}
+		return 0
+	}
+	d2 := 0.0
+	for i := range c {
+		d := float64(c[i] - center[i])
+		d2 += d * d
+	}
+	return math.Exp(-d2 / (2 * sigma * sigma))
+}
+
+// GaussianDistribution3D — объёмный аналог GaussianDistribution: нормированная
+// гауссиана с центром center и отклонением sigma в объёме width×height×depth.
+// Клетки с вероятностью ниже 1e-12 опускаются. Как и в двумерном случае,
+// sigma <= 0 даёт точечную массу в center (пустое распределение, если center
+// вне объёма).
+func GaussianDistribution3D(width, height, depth int, center [3]int, sigma float64) map[[3]int]float64 {
+	dist := make(map[[3]int]float64, width*height*depth)
+	total := 0.0
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			for z := 0; z < depth; z++ {
+				c := [3]int{x, y, z}
+				w := GaussFactor3D(c, center, sigma)
+				dist[c] = w
+				total += w
+			}
+		}
+	}
+	if total <= 0 {
+		return map[[3]int]float64{}
+	}
+	for c, w := range dist {
+		if p := w / total; p >= gaussianEpsilon {
+			dist[c] = p
+		} else {
+			delete(dist, c)
+		}
+	}
+	return dist
+}
+
+// World3D — дискретное пространство Width×Height×Depth, содержащее объёмные объекты.
+type World3D struct {
+	Width   int
+	Height  int
+	Depth   int
+	Objects []*QuantumObject3D
+
+	rng *lockedRand // источник для объектов без собственного генератора
+}
+
+// NewWorld3D создаёт новый объёмный мир заданного размера.
+func NewWorld3D(width, height, depth int) *World3D {
+	return &World3D{Width: width, Height: height, Depth: depth}
+}
+
+// SetRand задаёт генератор мира. nil возвращает глобальный генератор.
+func (w *World3D) SetRand(rng *rand.Rand) {
+	w.rng = newLockedRand(rng)
+}
+
+// AddQuantumObject добавляет объект в мир.
+func (w *World3D) AddQuantumObject(obj *QuantumObject3D) {
+	w.Objects = append(w.Objects, obj)
+}
+
+// MeasureInteraction — объёмный аналог World.MeasureInteraction:
+// взаимодействие происходит только в совпадающих клетках (x,y,z).
+func (w *World3D) MeasureInteraction(obj1, obj2 *QuantumObject3D) {
+	if obj1.IsCollapsed && obj2.IsCollapsed {
+		return
+	}
+	obj1.NormalizeDistribution()
+	obj2.NormalizeDistribution()
+
+	joint := make(map[[3]int]float64)
+	for c, p1 := range obj1.CoordDist {
+		if p2 := obj2.CoordDist[c]; p1 > 0 && p2 > 0 {
+			joint[c] = p1 * p2
+		}
+	}
+	if len(joint) == 0 {
+		return
+	}
+
+	obj1.CoordDist = joint
+	obj2.CoordDist = make(map[[3]int]float64, len(joint))
+	for c, p := range joint {
+		obj2.CoordDist[c] = p
+	}
+	obj1.collapseFrom(w.rng)
+	obj2.collapseFrom(w.rng)
+}
+
+// CollapseAll коллапсирует все объекты в мире.
+func (w *World3D) CollapseAll() {
+	for _, obj := range w.Objects {
+		obj.collapseFrom(w.rng)
+	}
+}
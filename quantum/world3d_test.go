@@ -0,0 +1,78 @@
+package quantum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeasureInteraction3D(t *testing.T) {
+	world := NewWorld3D(3, 3, 3)
+	obj1 := NewQuantumObject3D("A", map[[3]int]float64{{1, 1, 0}: 1, {1, 1, 2}: 1})
+	obj2 := NewQuantumObject3D("B", map[[3]int]float64{{1, 1, 2}: 1, {0, 0, 0}: 1})
+	world.AddQuantumObject(obj1)
+	world.AddQuantumObject(obj2)
+	world.MeasureInteraction(obj1, obj2)
+	if !obj1.IsCollapsed || !obj2.IsCollapsed {
+		t.Fatal("objects sharing a cell should collapse")
+	}
+	if obj1.FinalCoord != [3]int{1, 1, 2} || obj2.FinalCoord != [3]int{1, 1, 2} {
+		t.Errorf("expected both at (1,1,2), got %v and %v", obj1.FinalCoord, obj2.FinalCoord)
+	}
+
+	obj3 := NewQuantumObject3D("C", map[[3]int]float64{{2, 2, 0}: 1})
+	obj4 := NewQuantumObject3D("D", map[[3]int]float64{{2, 2, 1}: 1})
+	world.MeasureInteraction(obj3, obj4)
+	if obj3.IsCollapsed || obj4.IsCollapsed {
+		t.Error("objects on different layers should not interact")
+	}
+}
+
+func TestLiftAndUniform3D(t *testing.T) {
+	flat := NewQuantumObject("A", map[[2]int]float64{{0, 1}: 0.5, {2, 2}: 0.5})
+	obj := Lift(flat, 3)
+	if obj.CoordDist[[3]int{0, 1, 3}] != 0.5 || len(obj.CoordDist) != 2 {
+		t.Errorf("lifted distribution mismatch: %v", obj.CoordDist)
+	}
+
+	total := 0.0
+	for _, p := range UniformDistribution3D(2, 3, 4) {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-12 {
+		t.Errorf("uniform distribution should sum to 1, got %f", total)
+	}
+}
+
+func TestGaussianDistribution3D(t *testing.T) {
+	dist := GaussianDistribution3D(5, 5, 5, [3]int{2, 2, 2}, 1)
+	total := 0.0
+	for c, p := range dist {
+		total += p
+		if p > dist[[3]int{2, 2, 2}] {
+			t.Errorf("cell %v outweighs the centre", c)
+		}
+	}
+	if math.Abs(total-1) > 1e-12 {
+		t.Errorf("distribution should sum to 1, got %f", total)
+	}
+	if dist[[3]int{1, 2, 2}] != dist[[3]int{2, 2, 3}] {
+		t.Error("distribution should be symmetric around the centre")
+	}
+
+	for _, sigma := range []float64{0, -1} {
+		if point := GaussianDistribution3D(5, 5, 5, [3]int{1, 2, 3}, sigma); len(point) != 1 || point[[3]int{1, 2, 3}] != 1 {
+			t.Errorf("sigma %v should give a point mass at the centre, got %v", sigma, point)
+		}
+	}
+	if outside := GaussianDistribution3D(5, 5, 5, [3]int{7, 2, 2}, 0); len(outside) != 0 {
+		t.Errorf("point mass outside the volume should be empty, got %v", outside)
+	}
+
+	world := NewWorld3D(5, 5, 5)
+	a := NewQuantumObject3D("A", GaussianDistribution3D(5, 5, 5, [3]int{2, 2, 2}, 1))
+	b := NewQuantumObject3D("B", map[[3]int]float64{{2, 2, 3}: 1})
+	world.MeasureInteraction(a, b)
+	if !a.IsCollapsed || a.FinalCoord != [3]int{2, 2, 3} {
+		t.Errorf("object should collapse into the only shared cell, got %v", a.FinalCoord)
+	}
+}
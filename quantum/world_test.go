@@ -1,11 +1,17 @@
 package quantum
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
 	"testing"
 )
 
 func TestNormalizationAfterInteraction(t *testing.T) {
-	world := NewWorld(5, 5)
+	world := NewWorld(WithSize(5, 5))
 	obj1 := NewQuantumObject("Obj1", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
 	obj2 := NewQuantumObject("Obj2", map[[2]int]float64{{0, 0}: 1, {2, 2}: 1})
 	world.AddQuantumObject(obj1)
@@ -25,12 +31,14 @@ func TestNormalizationAfterInteraction(t *testing.T) {
 }
 
 func TestInteractionDependenceOnDistance(t *testing.T) {
-	world := NewWorld(5, 5)
+	world := NewWorld(WithSize(5, 5))
 	obj1 := NewQuantumObject("Obj1", map[[2]int]float64{{0, 0}: 1})
 	obj2 := NewQuantumObject("Obj2", map[[2]int]float64{{4, 4}: 1})
 	world.AddQuantumObject(obj1)
 	world.AddQuantumObject(obj2)
-	world.MeasureInteraction(obj1, obj2)
+	if world.MeasureInteraction(obj1, obj2) {
+		t.Error("MeasureInteraction should report that disjoint objects did not meet")
+	}
 	if obj1.IsCollapsed || obj2.IsCollapsed {
 		t.Error("objects should not collapse when coordinates do not match")
 	}
@@ -39,14 +47,19 @@ func TestInteractionDependenceOnDistance(t *testing.T) {
 	obj4 := NewQuantumObject("Obj4", map[[2]int]float64{{2, 2}: 1})
 	world.AddQuantumObject(obj3)
 	world.AddQuantumObject(obj4)
-	world.MeasureInteraction(obj3, obj4)
+	if !world.MeasureInteraction(obj3, obj4) {
+		t.Error("MeasureInteraction should report a measurement when coordinates match")
+	}
 	if !obj3.IsCollapsed || !obj4.IsCollapsed {
 		t.Error("objects should collapse when coordinates match")
 	}
+	if world.MeasureInteraction(obj3, obj4) {
+		t.Error("measuring two collapsed objects should not count as a measurement")
+	}
 }
 
 func TestSystemStability(t *testing.T) {
-	world := NewWorld(5, 5)
+	world := NewWorld(WithSize(5, 5))
 	obj1 := NewQuantumObject("Obj1", map[[2]int]float64{{1, 1}: 1})
 	obj2 := NewQuantumObject("Obj2", map[[2]int]float64{{1, 1}: 1})
 	world.AddQuantumObject(obj1)
@@ -61,3 +74,861 @@ func TestSystemStability(t *testing.T) {
 		t.Error("collapsed object should not change coordinate")
 	}
 }
+
+func TestCollapseUsesInjectedRand(t *testing.T) {
+	dist := map[[2]int]float64{{0, 0}: 1, {1, 1}: 1}
+	rng := rand.New(rand.NewSource(7))
+	ref := rand.New(rand.NewSource(7))
+
+	obj := NewQuantumObjectWithRand("A", dist, rng)
+	obj.Collapse()
+	ref.Float64()
+	if rng.Float64() != ref.Float64() {
+		t.Error("Collapse should draw exactly one value from the object's generator")
+	}
+
+	world := NewWorldWithRand(5, 5, rng)
+	world.AddQuantumObject(NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1}))
+	world.AddQuantumObject(NewQuantumObject("C", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1}))
+	world.CollapseAll()
+	ref.Float64()
+	ref.Float64()
+	if rng.Float64() != ref.Float64() {
+		t.Error("CollapseAll should draw from the world's generator")
+	}
+}
+
+func TestCollapseReproducibleWithSeed(t *testing.T) {
+	dist := func() map[[2]int]float64 {
+		d := make(map[[2]int]float64)
+		for x := 0; x < 4; x++ {
+			for y := 0; y < 4; y++ {
+				d[[2]int{x, y}] = 1
+			}
+		}
+		return d
+	}
+	run := func() [][2]int {
+		rng := rand.New(rand.NewSource(42))
+		var seq [][2]int
+		for i := 0; i < 10; i++ {
+			obj := NewQuantumObjectWithRand("A", dist(), rng)
+			obj.Collapse()
+			seq = append(seq, obj.FinalCoord)
+		}
+		return seq
+	}
+	first, second := run(), run()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("run diverged at step %d: %v vs %v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	obj := NewQuantumObject("Tree", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	world.AddQuantumObject(obj)
+
+	clone, err := world.CloneObject("Tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Name != "Tree"+CloneSuffix || len(world.Objects) != 2 {
+		t.Errorf("clone should be added under a new name, got %q", clone.Name)
+	}
+	clone.Collapse()
+	if obj.IsCollapsed || len(obj.CoordDist) != 2 {
+		t.Error("collapsing the clone must not affect the original")
+	}
+	if _, err := world.CloneObject("Missing"); err == nil {
+		t.Error("cloning a missing object should fail")
+	}
+}
+
+func TestCollapseToRegion(t *testing.T) {
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1, {4, 4}: 2})
+	if err := obj.CollapseToRegion(0, 0, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if obj.IsCollapsed {
+		t.Error("region collapse should keep the object in superposition")
+	}
+	if _, ok := obj.CoordDist[[2]int{4, 4}]; ok || obj.CoordDist[[2]int{0, 0}] != 0.5 {
+		t.Errorf("distribution should be restricted and renormalized, got %v", obj.CoordDist)
+	}
+	if err := obj.CollapseToRegion(3, 3, 4, 4); err == nil {
+		t.Error("empty region should return an error")
+	}
+	if len(obj.CoordDist) != 2 {
+		t.Error("failed region collapse must leave the distribution untouched")
+	}
+}
+
+func TestResetRestoresSuperposition(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {1, 1}: 3})
+	world.AddQuantumObject(obj)
+	branch := obj.Clone()
+
+	world.CollapseAll()
+	world.ResetAll()
+	if obj.IsCollapsed || obj.FinalCoord != [2]int{} {
+		t.Error("reset object should be uncollapsed with a zero FinalCoord")
+	}
+	if obj.CoordDist[[2]int{0, 0}] != 0.25 || obj.CoordDist[[2]int{1, 1}] != 0.75 {
+		t.Errorf("pre-collapse distribution should be restored, got %v", obj.CoordDist)
+	}
+
+	branch.Collapse()
+	other := branch.Clone()
+	other.Reset()
+	if !branch.IsCollapsed || other.IsCollapsed || len(other.CoordDist) != 2 {
+		t.Error("resetting a clone must not affect the collapsed original")
+	}
+}
+
+func TestMeasureInteractionWithin(t *testing.T) {
+	world := NewWorld(WithSize(10, 10))
+	person := NewQuantumObject("Person", map[[2]int]float64{{0, 0}: 1})
+	tree := NewQuantumObject("Tree", map[[2]int]float64{{2, 0}: 1, {9, 9}: 1})
+	world.MeasureInteractionWithin(person, tree, 2)
+	if !person.IsCollapsed || !tree.IsCollapsed || tree.FinalCoord != [2]int{2, 0} {
+		t.Errorf("tree within radius should be seen at (2,0), got %v", tree)
+	}
+
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 0}: 1})
+	world.MeasureInteractionWithin(a, b, 0)
+	if a.IsCollapsed || b.IsCollapsed {
+		t.Error("radius 0 should require exact co-location")
+	}
+}
+
+func TestResetWithCopiesDistribution(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	world.AddQuantumObject(obj)
+	world.CollapseAll()
+
+	fresh := map[[2]int]float64{{1, 1}: 1, {2, 2}: 1}
+	world.ResetAllWith(func(name string) map[[2]int]float64 { return fresh })
+	if obj.IsCollapsed || len(obj.CoordDist) != 2 {
+		t.Fatalf("object should be reseeded, got %v", obj.CoordDist)
+	}
+	fresh[[2]int{3, 3}] = 1
+	if len(obj.CoordDist) != 2 {
+		t.Error("ResetWith must copy the provided map, not alias it")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	obj := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1000, {1, 1}: 1000, {4, 4}: 1e-9})
+	world.AddQuantumObject(obj)
+	if n := world.PruneAll(1e-6); n != 1 {
+		t.Errorf("expected 1 cell removed, got %d", n)
+	}
+	if _, ok := obj.CoordDist[[2]int{4, 4}]; ok {
+		t.Error("negligible cell should be pruned")
+	}
+	if obj.CoordDist[[2]int{0, 0}] != 0.5 || obj.CoordDist[[2]int{1, 1}] != 0.5 {
+		t.Errorf("remaining distribution should be renormalized, got %v", obj.CoordDist)
+	}
+
+	flat := NewQuantumObject("F", map[[2]int]float64{{0, 0}: 1, {1, 1}: 2, {2, 2}: 1})
+	if n := flat.Prune(0.9); n != 2 || len(flat.CoordDist) != 1 || flat.CoordDist[[2]int{1, 1}] != 1 {
+		t.Errorf("pruning everything should keep the largest cell, removed %d: %v", n, flat.CoordDist)
+	}
+
+	posterior := NewQuantumObject("P", UniformDistribution(30, 30))
+	likelihood := NewGaussianQuantumObject("L", 15, 15, 3, 30, 30)
+	for range 5 {
+		posterior = posterior.Multiply(likelihood)
+	}
+	before := len(posterior.CoordDist)
+	posterior.Prune(1e-9)
+	if len(posterior.CoordDist) >= before/4 {
+		t.Errorf("repeated Gaussian updates should prune well, %d -> %d cells", before, len(posterior.CoordDist))
+	}
+}
+
+func gridDist(width, height int) map[[2]int]float64 {
+	dist := make(map[[2]int]float64, width*height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			dist[[2]int{x, y}] = float64(1 + (x*7+y*3)%5)
+		}
+	}
+	return dist
+}
+
+func TestMeasureInteractionMatchesPairwise(t *testing.T) {
+	d1, d2 := gridDist(6, 6), gridDist(6, 6)
+	delete(d2, [2]int{2, 2})
+	d2[[2]int{9, 9}] = 1
+
+	world := NewWorld(WithSize(6, 6))
+	want1, want2 := world.pairJoint(ExactKernel)(d1, d2)
+	got1, got2 := exactJoint(d1, d2)
+	if !reflect.DeepEqual(got1, want1) || !reflect.DeepEqual(got2, want2) {
+		t.Error("map intersection should reproduce the pairwise weights exactly")
+	}
+}
+
+func benchmarkMeasure(b *testing.B, joint func(w *World) jointFunc) {
+	world := NewWorld(WithSize(200, 200))
+	d1, d2 := gridDist(200, 200), gridDist(200, 200)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		a, c := NewQuantumObject("A", copyDist(d1)), NewQuantumObject("B", copyDist(d2))
+		b.StartTimer()
+		world.measure("benchmark", a, c, joint(world))
+	}
+}
+
+func BenchmarkMeasureInteraction(b *testing.B) {
+	benchmarkMeasure(b, func(*World) jointFunc { return exactJoint })
+}
+
+// BenchmarkMeasureInteractionPairwise — прежний перебор всех пар клеток, O(N·M).
+func BenchmarkMeasureInteractionPairwise(b *testing.B) {
+	benchmarkMeasure(b, func(w *World) jointFunc { return w.pairJoint(ExactKernel) })
+}
+
+func TestOnCollapseCallbacks(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", map[[2]int]float64{{1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{1, 1}: 1})
+	c := NewQuantumObject("C", map[[2]int]float64{{2, 2}: 1})
+	world.AddQuantumObject(a)
+	world.AddQuantumObject(b)
+	world.AddQuantumObject(c)
+
+	var seen []string
+	record := func(obj *QuantumObject) {
+		if !obj.IsCollapsed {
+			t.Errorf("%s reported before IsCollapsed was set", obj.Name)
+		}
+		seen = append(seen, obj.Name)
+	}
+	counter := 0
+	count := func(*QuantumObject) { counter++ }
+	world.OnCollapse(record)
+	world.OnCollapse(count)
+
+	var coord [2]int
+	c.OnCollapse(func(fc [2]int) { coord = fc })
+
+	world.MeasureInteraction(a, b)
+	world.RemoveOnCollapse(count)
+	c.Collapse()
+
+	if len(seen) != 3 || seen[0] != "A" || seen[1] != "B" || seen[2] != "C" {
+		t.Errorf("unexpected collapse order: %v", seen)
+	}
+	if counter != 2 {
+		t.Errorf("removed callback should stop firing, got %d calls", counter)
+	}
+	if coord != [2]int{2, 2} {
+		t.Errorf("object hook should receive FinalCoord, got %v", coord)
+	}
+}
+
+func TestOnCollapseRegistrationOrder(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	obj := NewQuantumObject("A", map[[2]int]float64{{2, 1}: 1})
+	world.AddQuantumObject(obj)
+
+	var calls []int
+	for i := range 3 {
+		world.OnCollapse(func(o *QuantumObject) {
+			if !o.IsCollapsed || o.FinalCoord != [2]int{2, 1} {
+				t.Errorf("callback %d saw unsettled object %v", i, o)
+			}
+			calls = append(calls, i)
+		})
+	}
+	world.CollapseAll()
+	if !slices.Equal(calls, []int{0, 1, 2}) {
+		t.Errorf("callbacks should run in registration order, got %v", calls)
+	}
+}
+
+func TestMeasureInteractionRadius(t *testing.T) {
+	world := NewWorldWithRand(10, 10, rand.New(rand.NewSource(5)))
+	for i := 0; i < 20; i++ {
+		a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {5, 5}: 1})
+		b := NewQuantumObject("B", map[[2]int]float64{{1, 0}: 1, {5, 6}: 1})
+		if !world.MeasureInteractionRadius(a, b, 1.5) || !a.IsCollapsed || !b.IsCollapsed {
+			t.Fatal("objects within radius should collapse")
+		}
+		if world.Distance(a.FinalCoord, b.FinalCoord) > 1.5 {
+			t.Fatalf("outcomes should form a pair within radius: %v %v", a, b)
+		}
+	}
+
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 1}: 1})
+	if world.MeasureInteractionRadius(a, b, 0) || a.IsCollapsed || b.IsCollapsed {
+		t.Error("radius 0 should require exact co-location")
+	}
+}
+
+func TestSoftMeasure(t *testing.T) {
+	obj := NewQuantumObject("A", gridDist(5, 5))
+	center := [2]int{2, 2}
+	prev := obj.ProbabilityAt(2, 2)
+	for i := 0; i < 5; i++ {
+		obj.SoftMeasure(center, 0.5)
+		if obj.IsCollapsed {
+			t.Fatal("soft measurement must not collapse")
+		}
+		p := obj.ProbabilityAt(2, 2)
+		if p <= prev {
+			t.Fatalf("mass should concentrate at center: %f after %f", p, prev)
+		}
+		prev = p
+	}
+
+	obj.SoftMeasure(center, 1e6)
+	if p := obj.ProbabilityAt(2, 2); math.Abs(p-1) > 1e-9 {
+		t.Errorf("very strong measurement should leave the center, got %f", p)
+	}
+
+	hard := NewQuantumObject("B", gridDist(5, 5))
+	hard.SoftMeasure([2]int{1, 3}, MaxStrength)
+	if !hard.IsCollapsed || hard.FinalCoord != [2]int{1, 3} {
+		t.Errorf("MaxStrength should collapse at center, got %v", hard)
+	}
+}
+
+func TestFind(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a1 := NewQuantumObject("A", gridDist(3, 3))
+	b := NewQuantumObject("B", gridDist(3, 3))
+	a2 := NewQuantumObject("A", gridDist(3, 3))
+	world.AddQuantumObject(a1)
+	world.AddQuantumObject(b)
+	world.AddQuantumObject(a2)
+
+	if obj, ok := world.Find("A"); !ok || obj != a1 {
+		t.Errorf("Find should return the first object added, got %v", obj)
+	}
+	if all := world.FindAll("A"); len(all) != 2 || all[0] != a1 || all[1] != a2 {
+		t.Errorf("FindAll should return duplicates in insertion order, got %v", all)
+	}
+	if _, ok := world.Find("missing"); ok {
+		t.Error("missing name should not be found")
+	}
+	if len(world.FindAll("missing")) != 0 {
+		t.Error("FindAll on a missing name should be empty")
+	}
+
+	b.Name = "C"
+	if _, ok := world.Find("B"); ok {
+		t.Error("renamed object should not be found under its old name")
+	}
+	direct := NewQuantumObject("C", gridDist(3, 3))
+	world.Objects = append(world.Objects, direct)
+	if all := world.FindAll("C"); len(all) != 2 || all[0] != b || all[1] != direct {
+		t.Errorf("index should follow direct edits of Objects, got %v", all)
+	}
+
+	world.Objects = []*QuantumObject{direct, a2, a1, direct}
+	if all := world.FindAll("A"); len(all) != 2 || all[0] != a2 || all[1] != a1 {
+		t.Errorf("replacing Objects with a slice of the same length should reset the index, got %v", all)
+	}
+	if all := world.FindAll("C"); len(all) != 2 || all[0] != direct || all[1] != direct {
+		t.Errorf("index should follow the replaced Objects, got %v", all)
+	}
+}
+
+func TestFindAfterUnmarshal(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	for _, name := range []string{"old1", "old2", "old3"} {
+		world.AddQuantumObject(NewQuantumObject(name, gridDist(3, 3)))
+	}
+	if _, ok := world.Find("old1"); !ok {
+		t.Fatal("old1 should be found before unmarshalling")
+	}
+
+	other := NewWorld(WithSize(3, 3))
+	other.AddQuantumObject(NewQuantumObject("new1", gridDist(3, 3)))
+	other.AddQuantumObject(NewQuantumObject("new2", gridDist(3, 3)))
+	data, err := json.Marshal(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, world); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := world.Find("old1"); ok {
+		t.Error("objects removed by UnmarshalJSON should not be found")
+	}
+	if _, ok := world.Find("new2"); !ok {
+		t.Error("unmarshalled objects should be found")
+	}
+}
+
+func TestRemoveQuantumObject(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1, {4, 4}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {4, 4}: 1})
+	c1 := NewQuantumObject("C", gridDist(2, 2))
+	c2 := NewQuantumObject("C", gridDist(2, 2))
+	for _, obj := range []*QuantumObject{a, b, c1, c2} {
+		world.AddQuantumObject(obj)
+	}
+	world.Entangle(a, b, func(c [2]int) [2]int { return c })
+	world.Find("A") // построить индекс имён
+
+	if !world.RemoveQuantumObject(a) {
+		t.Fatal("A should be found")
+	}
+	if world.RemoveQuantumObject(a) {
+		t.Error("second removal should report false")
+	}
+	if _, ok := world.Find("A"); ok {
+		t.Error("name index should forget removed objects")
+	}
+	if len(b.entangled) != 0 || len(a.entangled) != 0 || a.world != nil {
+		t.Error("removed object should be fully detached")
+	}
+	a.Collapse()
+	if b.IsCollapsed {
+		t.Error("collapsing a removed object must not affect former partners")
+	}
+
+	if n := world.RemoveByName("C"); n != 2 {
+		t.Errorf("expected 2 removals, got %d", n)
+	}
+	if len(world.Objects) != 1 || world.Objects[0] != b {
+		t.Errorf("only B should remain, got %v", world.Objects)
+	}
+}
+
+func TestCollapseWith(t *testing.T) {
+	dist := map[[2]int]float64{{0, 0}: 1, {0, 1}: 2, {1, 0}: 1}
+	for _, tc := range []struct {
+		r    float64
+		want [2]int
+	}{
+		{0, [2]int{0, 0}},
+		{0.25, [2]int{0, 0}},
+		{0.26, [2]int{0, 1}},
+		{0.75, [2]int{0, 1}},
+		{0.9, [2]int{1, 0}},
+		{-3, [2]int{0, 0}},
+		{7, [2]int{1, 0}},
+	} {
+		obj := NewQuantumObject("A", copyDist(dist))
+		obj.CollapseWith(tc.r)
+		if !obj.IsCollapsed || obj.FinalCoord != tc.want {
+			t.Errorf("r=%v: expected %v, got %v", tc.r, tc.want, obj)
+		}
+	}
+}
+
+func TestCollapseRoundingGap(t *testing.T) {
+	// нормированные веса 0.3/2.1 в сумме дают чуть меньше 1
+	dist := make(map[[2]int]float64)
+	for i := 0; i < 7; i++ {
+		dist[[2]int{i, 0}] = 0.3
+	}
+	dist[[2]int{7, 0}] = 0
+	obj := NewQuantumObject("A", dist)
+	obj.CollapseWith(math.Nextafter(1, 0))
+	if !obj.IsCollapsed || obj.FinalCoord != [2]int{6, 0} {
+		t.Errorf("r in the rounding gap should pick the last non-zero cell, got %v", obj)
+	}
+}
+
+func TestCollapseIgnoresInsertionOrder(t *testing.T) {
+	var coords [][2]int
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			coords = append(coords, [2]int{x, y})
+		}
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		forward := make(map[[2]int]float64)
+		backward := make(map[[2]int]float64)
+		for i, c := range coords {
+			forward[c] = float64(1 + i%3)
+		}
+		for i := len(coords) - 1; i >= 0; i-- {
+			backward[coords[i]] = float64(1 + i%3)
+		}
+		a := NewQuantumObjectWithRand("A", forward, rand.New(rand.NewSource(seed)))
+		b := NewQuantumObjectWithRand("B", backward, rand.New(rand.NewSource(seed)))
+		a.Collapse()
+		b.Collapse()
+		if a.FinalCoord != b.FinalCoord {
+			t.Fatalf("seed %d: outcome depends on insertion order: %v vs %v", seed, a, b)
+		}
+	}
+}
+
+func TestMeasureInteractionNSharpens(t *testing.T) {
+	joint := func(n int) map[[2]int]float64 {
+		d1 := map[[2]int]float64{{0, 0}: 3, {1, 1}: 1}
+		d2 := map[[2]int]float64{{0, 0}: 1, {1, 1}: 1, {2, 2}: 2}
+		got, _ := powJoint(exactJoint, n)(d1, d2)
+		total := SparseDistribution(got).Total()
+		for c, p := range got {
+			got[c] = p / total
+		}
+		return got
+	}
+	one, three := joint(1), joint(3)
+	if one[[2]int{0, 0}] >= three[[2]int{0, 0}] {
+		t.Errorf("repeated observation should sharpen the joint: %v vs %v", one, three)
+	}
+	if math.Abs(three[[2]int{0, 0}]-27.0/28) > 1e-12 {
+		t.Errorf("three rounds should weight cells by (p1·p2)³, got %v", three)
+	}
+
+	world := NewWorld(WithSize(3, 3))
+	a := NewQuantumObject("A", map[[2]int]float64{{0, 0}: 3, {1, 1}: 1})
+	b := NewQuantumObject("B", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1, {2, 2}: 2})
+	world.MeasureInteractionN(a, b, 3)
+	common := map[[2]int]bool{{0, 0}: true, {1, 1}: true}
+	if !a.IsCollapsed || !b.IsCollapsed || !common[a.FinalCoord] || !common[b.FinalCoord] {
+		t.Errorf("objects should collapse into common cells after the rounds: %v %v", a, b)
+	}
+}
+
+func TestMeasureInteractionReliability(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	dist := func() (*QuantumObject, *QuantumObject) {
+		return NewQuantumObject("A", map[[2]int]float64{{0, 0}: 0.5, {1, 1}: 0.5}),
+			NewQuantumObject("B", map[[2]int]float64{{0, 0}: 0.5, {2, 2}: 0.5})
+	}
+
+	a, b := dist()
+	world.MeasureInteractionReliability(a, b, 0)
+	if a.CoordDist[[2]int{0, 0}] != 0.5 || b.CoordDist[[2]int{2, 2}] != 0.5 {
+		t.Errorf("zero reliability should be a no-op: %v %v", a.CoordDist, b.CoordDist)
+	}
+
+	a, b = dist()
+	world.MeasureInteractionReliability(a, b, 0.5)
+	if a.IsCollapsed || b.IsCollapsed {
+		t.Fatal("noisy observation should not collapse the objects")
+	}
+	if got := a.CoordDist[[2]int{0, 0}]; math.Abs(got-0.75) > 1e-12 {
+		t.Errorf("A(0,0) = %v, want 0.5·1 + 0.5·0.5", got)
+	}
+	if got := b.CoordDist[[2]int{2, 2}]; math.Abs(got-0.25) > 1e-12 {
+		t.Errorf("B(2,2) = %v, want 0.5·0 + 0.5·0.5", got)
+	}
+
+	a, b = dist()
+	world.MeasureInteractionReliability(a, b, 1)
+	if a.FinalCoord != [2]int{0, 0} || b.FinalCoord != [2]int{0, 0} {
+		t.Errorf("full reliability should measure and collapse: %v %v", a, b)
+	}
+}
+
+func TestRemoveObject(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	a1 := NewQuantumObject("A", gridDist(2, 2))
+	a2 := NewQuantumObject("A", gridDist(2, 2))
+	b := NewQuantumObject("B", gridDist(2, 2))
+	for _, obj := range []*QuantumObject{a1, b, a2} {
+		world.AddQuantumObject(obj)
+	}
+
+	if err := world.RemoveObject("A"); err != nil {
+		t.Fatal(err)
+	}
+	if len(world.Objects) != 2 || world.Objects[0] != b || world.Objects[1] != a2 {
+		t.Errorf("only the first match should be removed, got %v", world.Objects)
+	}
+	err := world.RemoveObject("missing")
+	var notFound *ObjectNotFoundError
+	if !errors.As(err, &notFound) || notFound.Name != "missing" {
+		t.Errorf("expected ObjectNotFoundError, got %v", err)
+	}
+	if _, err := world.CloneObject("missing"); !errors.As(err, &notFound) {
+		t.Errorf("CloneObject should report ObjectNotFoundError, got %v", err)
+	}
+
+	if err := world.RemoveObjectByIndex(1); err != nil || len(world.Objects) != 1 || world.Objects[0] != b {
+		t.Errorf("index removal failed: %v, %v", err, world.Objects)
+	}
+	if err := world.RemoveObjectByIndex(5); err == nil {
+		t.Error("out-of-range index should be an error")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	tree := NewQuantumObject("tree", gridDist(2, 2), WithMetadata("kind", "plant"), WithMetadata("scenario", "yard"))
+	john := NewQuantumObject("John", gridDist(2, 2), WithMetadata("kind", "person"))
+	world.AddQuantumObject(tree)
+	world.AddQuantumObject(john)
+	world.AddQuantumObject(NewQuantumObject("seed", gridDist(2, 2)))
+
+	if got := world.FilterByMetadata("kind", "plant"); len(got) != 1 || got[0] != tree {
+		t.Errorf("expected only the tree, got %v", got)
+	}
+	if got := world.FilterByMetadata("scenario", "other"); len(got) != 0 {
+		t.Errorf("no object should match, got %v", got)
+	}
+
+	clone := tree.Clone()
+	clone.Metadata["kind"] = "changed"
+	if tree.Metadata["kind"] != "plant" {
+		t.Error("clone should not share metadata with the original")
+	}
+}
+
+func TestObjectOptions(t *testing.T) {
+	var got [2]int
+	opts := []ObjectOption{
+		WithDistribution(map[[2]int]float64{{0, 0}: 1, {1, 1}: 3}),
+		WithCollapseStrategy(ArgmaxStrategy{}),
+		WithRand(rand.New(rand.NewSource(1))),
+		WithOnCollapse(func(c [2]int) { got = c }),
+		WithMetadata("kind", "probe"),
+	}
+	obj := NewQuantumObject("A", nil, opts...)
+	if obj.rng == nil || obj.Metadata["kind"] != "probe" {
+		t.Error("rng and metadata options should be applied")
+	}
+	obj.Collapse()
+	if obj.FinalCoord != [2]int{1, 1} || got != [2]int{1, 1} {
+		t.Errorf("strategy and hook options should apply on collapse: %v, hook saw %v", obj.FinalCoord, got)
+	}
+}
+
+func TestMeasureInteractionGroup(t *testing.T) {
+	world := NewWorld(WithSize(5, 5))
+	person := NewQuantumObject("person", map[[2]int]float64{{0, 0}: 1, {2, 2}: 1, {4, 4}: 1})
+	dog := NewQuantumObject("dog", map[[2]int]float64{{2, 2}: 1, {4, 4}: 1, {1, 3}: 1})
+	tree := NewQuantumObject("tree", map[[2]int]float64{{2, 2}: 1, {0, 0}: 1})
+	if !world.MeasureInteractionGroup(person, dog, tree) {
+		t.Error("group with a common cell should report a measurement")
+	}
+	for _, obj := range []*QuantumObject{person, dog, tree} {
+		if !obj.IsCollapsed || obj.FinalCoord != [2]int{2, 2} {
+			t.Errorf("%v should collapse at the only common cell", obj)
+		}
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		world := NewWorldWithRand(5, 5, rand.New(rand.NewSource(seed)))
+		shared := map[[2]int]float64{{0, 0}: 1, {2, 2}: 1, {4, 4}: 1}
+		x := NewQuantumObject("x", copyDist(shared))
+		y := NewQuantumObject("y", copyDist(shared))
+		z := NewQuantumObject("z", copyDist(shared))
+		world.MeasureInteractionGroup(x, y, z)
+		if !x.IsCollapsed || x.FinalCoord != y.FinalCoord || y.FinalCoord != z.FinalCoord {
+			t.Fatalf("seed %d: group should share one final cell, got %v %v %v",
+				seed, x.FinalCoord, y.FinalCoord, z.FinalCoord)
+		}
+	}
+
+	fixed := NewQuantumObject("fixed", map[[2]int]float64{{4, 4}: 1})
+	fixed.Collapse()
+	prior := fixed.prior
+	free := NewQuantumObject("free", map[[2]int]float64{{2, 2}: 1, {4, 4}: 1})
+	world.MeasureInteractionGroup(fixed, free)
+	if free.FinalCoord != [2]int{4, 4} || fixed.CoordDist[[2]int{4, 4}] != 1 || len(fixed.CoordDist) != 1 {
+		t.Errorf("collapsed member should pin the group and stay intact: %v %v", fixed, free)
+	}
+	if !reflect.DeepEqual(fixed.prior, prior) {
+		t.Errorf("collapsed member's prior must not change, got %v", fixed.prior)
+	}
+
+	a := NewQuantumObject("a", map[[2]int]float64{{0, 0}: 1, {1, 1}: 1})
+	b := NewQuantumObject("b", map[[2]int]float64{{1, 1}: 1, {2, 2}: 1})
+	c := NewQuantumObject("c", map[[2]int]float64{{2, 2}: 1, {0, 0}: 1})
+	if world.MeasureInteractionGroup(a, b, c) || a.IsCollapsed || b.IsCollapsed || c.IsCollapsed {
+		t.Error("without a cell common to all, the group measurement is a no-op")
+	}
+	if len(a.CoordDist) != 2 {
+		t.Errorf("no-op must keep distributions, got %v", a.CoordDist)
+	}
+	if world.MeasureInteractionGroup() || world.MeasureInteractionGroup(person, dog) {
+		t.Error("empty or fully collapsed groups should not report a measurement")
+	}
+}
+
+func TestCollapseByPriority(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	for _, name := range []string{"screen", "extra1", "particle", "detector", "extra2"} {
+		world.AddQuantumObject(NewQuantumObject(name, map[[2]int]float64{{1, 1}: 1}))
+	}
+	var seen []string
+	world.OnCollapse(func(obj *QuantumObject) { seen = append(seen, obj.Name) })
+
+	world.CollapseByPriority(map[string]int{"detector": 0, "particle": 1, "screen": 2})
+	want := []string{"detector", "particle", "screen", "extra1", "extra2"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("collapse order = %v, want %v", seen, want)
+	}
+	var order []string
+	for _, obj := range world.Objects {
+		order = append(order, obj.Name)
+	}
+	if !slices.Equal(order, []string{"screen", "extra1", "particle", "detector", "extra2"}) {
+		t.Errorf("world object order should be unchanged, got %v", order)
+	}
+}
+
+func TestNewWorldOptions(t *testing.T) {
+	if w := NewWorld(); w.Width != 0 || w.Height != 0 || w.Boundary != Bounded {
+		t.Errorf("NewWorld() should be a 0×0 bounded world, got %v", w)
+	}
+	var zero World
+	zero.AddQuantumObject(NewQuantumObject("A", map[[2]int]float64{{0, 0}: 1}))
+	zero.CollapseAll()
+	if obj, ok := zero.Find("A"); !ok || !obj.IsCollapsed {
+		t.Error("zero World should be usable")
+	}
+
+	w := NewWorld(
+		WithDecoherence(0.5),
+		WithSize(4, 3),
+		WithTopology(Toroidal),
+		WithNeighborhood(Moore),
+		WithMinProbability(1e-6),
+		WithHistory(2),
+		WithStrictMode(true),
+		WithRNG(rand.New(rand.NewSource(1))),
+		WithEvolution(NeighbourDiffusion(0.5)),
+	)
+	if w.Width != 4 || w.Height != 3 || w.Boundary != Toroidal || w.Neighborhood != Moore {
+		t.Errorf("size and topology options not applied: %+v", w)
+	}
+	if d, ok := w.Decoherence.(UniformDecoherence); !ok || d != (UniformDecoherence{Width: 4, Height: 3, Rate: 0.5}) {
+		t.Errorf("decoherence should use the final world size, got %#v", w.Decoherence)
+	}
+	if w.MinProbability() != 1e-6 || w.historyDepth != 2 || !w.strict || w.rng == nil || w.Evolution == nil {
+		t.Error("min probability, history, strict mode, rng or evolution option not applied")
+	}
+	if s := NewWorldSimple(4, 3); s.Width != 4 || s.Height != 3 {
+		t.Errorf("NewWorldSimple(4, 3) = %v", s)
+	}
+}
+
+func TestSetMinProbability(t *testing.T) {
+	// Хвостовая клетка с весом 1e-12 выбирается при r у самой единицы.
+	dist := func() map[[2]int]float64 { return map[[2]int]float64{{0, 0}: 1, {1, 0}: 1e-12} }
+	r := math.Nextafter(1, 0)
+
+	world := NewWorld(WithSize(2, 1))
+	if world.MinProbability() != 0 {
+		t.Errorf("default floor = %g", world.MinProbability())
+	}
+	tail := NewQuantumObject("tail", dist())
+	world.AddQuantumObject(tail)
+	tail.CollapseWith(r)
+	if tail.FinalCoord != [2]int{1, 0} {
+		t.Fatalf("without a floor the tail cell should be reachable, got %v", tail.FinalCoord)
+	}
+
+	world.SetMinProbability(1e-10)
+	floored := NewQuantumObject("floored", dist())
+	world.AddQuantumObject(floored)
+	floored.NormalizeDistribution()
+	if len(floored.CoordDist) != 1 || floored.CoordDist[[2]int{0, 0}] != 1 {
+		t.Errorf("entries below the floor should be dropped: %v", floored.CoordDist)
+	}
+	floored.CollapseWith(r)
+	if floored.FinalCoord != [2]int{0, 0} {
+		t.Errorf("collapse should ignore entries below the floor, got %v", floored.FinalCoord)
+	}
+
+	// порог сравнивается с нормированными вероятностями: крупные веса
+	// не отсекаются, а мелкие не уничтожают распределение целиком
+	world.SetMinProbability(0.4)
+	heavy := NewQuantumObject("heavy", map[[2]int]float64{{0, 0}: 50, {1, 0}: 10})
+	world.AddQuantumObject(heavy)
+	heavy.NormalizeDistribution()
+	if len(heavy.CoordDist) != 1 || heavy.CoordDist[[2]int{0, 0}] != 1 {
+		t.Errorf("floor should apply after normalization: %v", heavy.CoordDist)
+	}
+	tiny := NewQuantumObject("tiny", map[[2]int]float64{{0, 0}: 1e-3, {1, 0}: 2e-3})
+	world.AddQuantumObject(tiny)
+	tiny.NormalizeDistribution()
+	if len(tiny.CoordDist) != 1 || tiny.CoordDist[[2]int{1, 0}] != 1 {
+		t.Errorf("small raw weights should survive the floor: %v", tiny.CoordDist)
+	}
+	world.SetMinProbability(0.9)
+	even := NewQuantumObject("even", map[[2]int]float64{{0, 0}: 1, {1, 0}: 1})
+	world.AddQuantumObject(even)
+	even.NormalizeDistribution()
+	if len(even.CoordDist) != 1 || even.CoordDist[[2]int{0, 0}] != 1 {
+		t.Errorf("floor above every cell should keep the first most likely one: %v", even.CoordDist)
+	}
+
+	world.SetMinProbability(0)
+	if world.MinProbability() != 0 {
+		t.Error("non-positive floor should disable the threshold")
+	}
+}
+
+func TestCollapsedCounts(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	if !world.AllCollapsed() || world.UncollapsedCount() != 0 {
+		t.Error("empty world should count as fully collapsed")
+	}
+	for _, name := range []string{"A", "B", "C"} {
+		world.AddQuantumObject(NewQuantumObject(name, map[[2]int]float64{{0, 0}: 1, {1, 1}: 1}))
+	}
+	world.Objects[1].Collapse()
+	if world.CollapsedCount() != 1 || world.UncollapsedCount() != 2 || world.AllCollapsed() {
+		t.Errorf("counts = %d/%d", world.CollapsedCount(), world.UncollapsedCount())
+	}
+	steps := 0
+	for world.UncollapsedCount() > 0 {
+		world.Objects[steps].Collapse()
+		steps++
+	}
+	if !world.AllCollapsed() || steps != 3 {
+		t.Errorf("loop should finish after every object collapses, took %d steps", steps)
+	}
+}
+
+func TestWorldIterationHelpers(t *testing.T) {
+	world := NewWorld(WithSize(3, 3))
+	for _, name := range []string{"A", "B", "C"} {
+		world.AddQuantumObject(NewQuantumObject(name, map[[2]int]float64{{0, 0}: 1, {1, 1}: 1}))
+	}
+	world.Objects[1].CollapseWith(0)
+
+	var all, collapsed, open []string
+	world.ForEach(func(obj *QuantumObject) { all = append(all, obj.Name) })
+	world.ForEachCollapsed(func(obj *QuantumObject) { collapsed = append(collapsed, obj.Name) })
+	world.ForEachUncollapsed(func(obj *QuantumObject) { open = append(open, obj.Name) })
+	if !slices.Equal(all, []string{"A", "B", "C"}) || !slices.Equal(collapsed, []string{"B"}) || !slices.Equal(open, []string{"A", "C"}) {
+		t.Errorf("ForEach variants visited %v, %v, %v", all, collapsed, open)
+	}
+
+	got := world.Filter(func(obj *QuantumObject) bool { return obj.Name != "B" })
+	if len(got) != 2 || got[0] != world.Objects[0] || len(world.Objects) != 3 {
+		t.Errorf("Filter should return matching objects without changing the world, got %v", got)
+	}
+
+	moved := world.Map(func(obj *QuantumObject) *QuantumObject {
+		if obj.Name == "C" {
+			return nil
+		}
+		obj.Shift(1, 1, Toroidal)
+		return obj
+	})
+	if len(moved.Objects) != 2 || moved.Width != 3 {
+		t.Fatalf("Map should keep settings and drop nil results, got %v", moved)
+	}
+	if moved.Objects[1].FinalCoord != [2]int{1, 1} || world.Objects[1].FinalCoord != [2]int{0, 0} {
+		t.Error("Map should transform copies and leave the original world intact")
+	}
+	if obj, ok := moved.Find("A"); !ok || obj == world.Objects[0] {
+		t.Error("new world should index its own copies by name")
+	}
+}
@@ -0,0 +1,336 @@
+package quantum
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LoadWorldFromYAML читает сценарий из YAML-документа:
+//
+//	world:
+//	  width: 10
+//	  height: 10
+//	  boundary: toroidal   # необязательно: bounded, toroidal, reflecting
+//	objects:
+//	  - name: john
+//	    type: gaussian
+//	    cx: 3
+//	    cy: 3
+//	    sigma: 1.5
+//	  - name: tree
+//	    type: point
+//	    x: 4
+//	    y: 4
+//	interactions:
+//	  - measure: [john, tree]
+//
+// Типы объектов: gaussian (cx, cy, sigma), uniform, point (x, y),
+// ring (cx, cy, radius, width) и cells — явный список [x, y, вес];
+// collapsed: true вместе с at: [x, y] коллапсирует объект в клетку (x, y),
+// вероятность которой должна быть ненулевой.
+// Взаимодействия выполняются по порядку после добавления всех объектов.
+// Поддерживается подмножество YAML: блочные отображения и списки,
+// потоковые списки в квадратных скобках, комментарии и строки в кавычках.
+// Документ преобразуется в JSON и проверяется ValidateScenarioJSON, как
+// в LoadWorldFromJSON, поэтому ошибки схемы возвращаются как SchemaError.
+func LoadWorldFromYAML(r io.Reader) (*World, error) {
+	lines, err := yamlLines(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &yamlParser{lines: lines}
+	doc, err := p.block(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("yaml: line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	data, err := json.Marshal(yamlToJSON(doc))
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+	return loadScenario(data)
+}
+
+// yamlPlain — скаляр без кавычек; его тип определяет yamlToJSON.
+type yamlPlain string
+
+// yamlToJSON приводит разобранный документ к значениям encoding/json:
+// скаляры без кавычек true/false становятся bool, null и ~ — nil,
+// конечные числа — float64; прочие скаляры и строки в кавычках остаются строками.
+func yamlToJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = yamlToJSON(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = yamlToJSON(item)
+		}
+		return out
+	case yamlPlain:
+		switch v {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null", "~":
+			return nil
+		}
+		if f, err := strconv.ParseFloat(string(v), 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f
+		}
+		return string(v)
+	}
+	return v
+}
+
+// ExportYAML записывает мир в формате LoadWorldFromYAML: каждый объект
+// сохраняется как cells с ненормированными весами в порядке (x, y),
+// коллапсированный — с collapsed: true и at: [x, y]. Генераторы, запутанность и
+// обработчики не сохраняются.
+func (w *World) ExportYAML(out io.Writer) error {
+	bw := bufio.NewWriter(out)
+	fmt.Fprintf(bw, "world:\n  width: %d\n  height: %d\n", w.Width, w.Height)
+	if w.Boundary != Bounded {
+		fmt.Fprintf(bw, "  boundary: %s\n", w.Boundary)
+	}
+	objs := w.objects()
+	if len(objs) > 0 {
+		fmt.Fprintln(bw, "objects:")
+	}
+	for _, obj := range objs {
+		obj.mu.RLock()
+		dist := obj.coordsLocked()
+		fmt.Fprintf(bw, "  - name: %s\n    type: cells\n", yamlString(obj.Name))
+		if obj.IsCollapsed {
+			fmt.Fprintf(bw, "    collapsed: true\n    at: [%d, %d]\n", obj.FinalCoord[0], obj.FinalCoord[1])
+		}
+		fmt.Fprintln(bw, "    cells:")
+		for _, c := range sortedCoords(dist) {
+			fmt.Fprintf(bw, "      - [%d, %d, %s]\n", c[0], c[1],
+				strconv.FormatFloat(dist[c], 'g', -1, 64))
+		}
+		obj.mu.RUnlock()
+	}
+	return bw.Flush()
+}
+
+// yamlString возвращает строку как есть или в кавычках, если без них
+// она была бы прочитана иначе.
+func yamlString(s string) string {
+	if s == "" || strings.ContainsAny(s, ":#[],\"'") || strings.TrimSpace(s) != s || strings.HasPrefix(s, "-") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// yamlLine — значимая строка документа: отступ и содержимое без комментария.
+// Элемент списка "- x" разбивается на строку "-" и строку "x" с отступом на 2 больше.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+func yamlLines(r io.Reader) ([]yamlLine, error) {
+	var lines []yamlLine
+	sc := bufio.NewScanner(r)
+	num := 0
+	for sc.Scan() {
+		num++
+		raw := stripYAMLComment(sc.Text())
+		text := strings.TrimLeft(raw, " ")
+		if strings.TrimSpace(text) == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("yaml: line %d: tabs are not allowed in indentation", num)
+		}
+		indent := len(raw) - len(text)
+		text = strings.TrimRight(text, " \t\r")
+		for text == "-" || strings.HasPrefix(text, "- ") {
+			lines = append(lines, yamlLine{num: num, indent: indent, text: "-"})
+			rest := strings.TrimLeft(text[1:], " ")
+			indent += len(text) - len(rest)
+			text = rest
+		}
+		if text != "" {
+			lines = append(lines, yamlLine{num: num, indent: indent, text: text})
+		}
+	}
+	return lines, sc.Err()
+}
+
+// stripYAMLComment отбрасывает комментарий: '#' в начале или после пробела
+// вне кавычек.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block разбирает отображение, список или скаляр, начинающийся с текущей
+// строки, если её отступ не меньше minIndent.
+func (p *yamlParser) block(minIndent int) (any, error) {
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent < minIndent {
+		return nil, nil
+	}
+	first := p.lines[p.pos]
+	switch {
+	case first.text == "-":
+		return p.sequence(first.indent)
+	case yamlKey(first.text) != "":
+		return p.mapping(first.indent)
+	}
+	p.pos++
+	return parseYAMLFlow(first.text, first.num)
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	var items []any
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && p.lines[p.pos].text == "-" {
+		p.pos++
+		item, err := p.block(indent + 1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && p.lines[p.pos].text != "-" {
+		l := p.lines[p.pos]
+		key := yamlKey(l.text)
+		if key == "" {
+			return nil, fmt.Errorf("yaml: line %d: expected \"key: value\", got %q", l.num, l.text)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("yaml: line %d: duplicate key %q", l.num, key)
+		}
+		rest := strings.TrimSpace(l.text[len(key)+1:])
+		p.pos++
+		if rest != "" {
+			v, err := parseYAMLFlow(rest, l.num)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+		// Вложенный блок: глубже ключа или список на том же отступе.
+		child := indent + 1
+		if p.pos < len(p.lines) && p.lines[p.pos].indent == indent && p.lines[p.pos].text == "-" {
+			child = indent
+		}
+		v, err := p.block(child)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// yamlKey возвращает ключ строки "key: value" или "key:"; иначе пустую строку.
+func yamlKey(text string) string {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'") {
+		return ""
+	}
+	i := strings.Index(text, ":")
+	if i <= 0 || (i+1 < len(text) && text[i+1] != ' ') {
+		return ""
+	}
+	return strings.TrimSpace(text[:i])
+}
+
+// parseYAMLFlow разбирает скаляр или потоковый список вида [a, [b, c]].
+// Скаляры без кавычек возвращаются как yamlPlain, в кавычках — как string.
+func parseYAMLFlow(s string, num int) (any, error) {
+	v, rest, err := flowValue(strings.TrimSpace(s))
+	if err == nil && strings.TrimSpace(rest) != "" {
+		err = fmt.Errorf("unexpected %q", rest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("yaml: line %d: %w", num, err)
+	}
+	return v, nil
+}
+
+func flowValue(s string) (any, string, error) {
+	s = strings.TrimLeft(s, " ")
+	switch {
+	case strings.HasPrefix(s, "["):
+		var items []any
+		s = strings.TrimLeft(s[1:], " ")
+		if strings.HasPrefix(s, "]") {
+			return items, s[1:], nil
+		}
+		for {
+			item, rest, err := flowValue(s)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			rest = strings.TrimLeft(rest, " ")
+			switch {
+			case strings.HasPrefix(rest, ","):
+				s = rest[1:]
+			case strings.HasPrefix(rest, "]"):
+				return items, rest[1:], nil
+			default:
+				return nil, "", fmt.Errorf("unterminated list")
+			}
+		}
+	case strings.HasPrefix(s, "\""):
+		end := 1
+		for end < len(s) && (s[end] != '"' || s[end-1] == '\\') {
+			end++
+		}
+		if end == len(s) {
+			return nil, "", fmt.Errorf("unterminated string")
+		}
+		str, err := strconv.Unquote(s[:end+1])
+		return str, s[end+1:], err
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+	}
+	end := strings.IndexAny(s, ",]")
+	if end < 0 {
+		end = len(s)
+	}
+	return yamlPlain(strings.TrimSpace(s[:end])), s[end:], nil
+}
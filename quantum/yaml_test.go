@@ -0,0 +1,134 @@
+package quantum
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+const sampleYAML = `# demo scenario
+world:
+  width: 8
+  height: 6
+objects:
+  - name: john   # walker
+    type: gaussian
+    cx: 3
+    cy: 3
+    sigma: 1.5
+  - name: tree
+    type: point
+    x: 3
+    y: 3
+  - name: "fog: thick"
+    type: cells
+    cells:
+      - [0, 0, 1]
+      - [1, 0, 3]
+interactions:
+  - measure: [john, tree]
+`
+
+func TestLoadWorldFromYAML(t *testing.T) {
+	world, err := LoadWorldFromYAML(strings.NewReader(sampleYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world.Width != 8 || world.Height != 6 || len(world.Objects) != 3 {
+		t.Fatalf("unexpected world %dx%d with %d objects", world.Width, world.Height, len(world.Objects))
+	}
+	john, _ := world.Find("john")
+	if !john.IsCollapsed || john.FinalCoord != [2]int{3, 3} {
+		t.Errorf("measure step should collapse john onto the tree: %v", john)
+	}
+	fog, ok := world.Find("fog: thick")
+	if !ok || fog.ProbabilityAt(1, 0) != 0.75 {
+		t.Errorf("quoted name and explicit cells should load: %v", fog)
+	}
+}
+
+func TestExportYAMLRoundTrip(t *testing.T) {
+	world := NewWorld(WithSize(5, 4))
+	world.Boundary = Toroidal
+	world.AddQuantumObject(NewGaussianQuantumObject("g", 2, 2, 1, 5, 4))
+	done := NewQuantumObject("- done", map[[2]int]float64{{1, 3}: 1})
+	world.AddQuantumObject(done)
+	done.Collapse()
+
+	var buf bytes.Buffer
+	if err := world.ExportYAML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := LoadWorldFromYAML(&buf)
+	if err != nil {
+		t.Fatalf("reload failed: %v\n%s", err, buf.String())
+	}
+	if restored.Boundary != Toroidal || len(restored.Objects) != 2 {
+		t.Fatalf("world settings lost: %+v", restored)
+	}
+	g, _ := restored.Find("g")
+	for c, p := range world.Objects[0].CoordDist {
+		if math.Abs(g.CoordDist[c]-p) > 1e-15 {
+			t.Fatalf("weight at %v changed: %g -> %g", c, p, g.CoordDist[c])
+		}
+	}
+	d, _ := restored.Find("- done")
+	if !d.IsCollapsed || d.FinalCoord != [2]int{1, 3} {
+		t.Errorf("collapsed state should survive the round trip: %v", d)
+	}
+}
+
+func TestLoadWorldFromYAMLErrors(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: blob\n", "unknown distribution type blob"},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: gaussian\n    cx: 1\n", `missing required field "cy"`},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: point\n    x: 1\n    y: [2\n", "line 8"},
+		{"world:\n  width: 3\n    height: 3\n", "line 3"},
+		{"objects: []\n", `missing required field "world"`},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: point\n    x: 1\n    y: 1\n    collapsed: true\n    at: [0, 0]\n", "zero probability"},
+	}
+	for _, tt := range tests {
+		_, err := LoadWorldFromYAML(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("error %v, want mention of %q", err, tt.want)
+		}
+	}
+
+	_, err := LoadWorldFromYAML(strings.NewReader("world:\n  width: 3\n  height: 3\ninteractions:\n  - measure: [a, b]\n"))
+	var nf *ObjectNotFoundError
+	if !errors.As(err, &nf) || nf.Name != "a" {
+		t.Errorf("unknown interaction object should be reported, got %v", err)
+	}
+}
+
+func TestLoadWorldFromYAMLSchema(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  SchemaErrorKind
+		path  string
+	}{
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: cells\n    cells:\n      - [0, 0, -1]\n", InvalidParameter, "/objects/0/cells/0/2"},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: cells\n    cells:\n      - [0.5, 0, 1]\n", WrongType, "/objects/0/cells/0/0"},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: point\n    x: 1.5\n    y: 1\n", WrongType, "/objects/0/x"},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: point\n    x: \"1\"\n    y: 1\n", WrongType, "/objects/0/x"},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: uniform\n    collapsed: true\n", MissingField, "/objects/0"},
+		{"world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: uniform\n    collapsed: yes\n", WrongType, "/objects/0/collapsed"},
+	}
+	for _, tt := range tests {
+		_, err := LoadWorldFromYAML(strings.NewReader(tt.input))
+		var se SchemaError
+		if !errors.As(err, &se) || se.Kind != tt.kind || se.Path != tt.path {
+			t.Errorf("%q: got %v, want %s at %q", tt.input, err, tt.kind, tt.path)
+		}
+	}
+
+	world, err := LoadWorldFromYAML(strings.NewReader("world:\n  width: 3\n  height: 3\nobjects:\n  - name: a\n    type: uniform\n    collapsed: true\n    at: [2, 1]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := world.Objects[0]; !a.IsCollapsed || a.FinalCoord != [2]int{2, 1} {
+		t.Errorf("collapsed object should settle at the given cell: %v", a)
+	}
+}